@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/edit4i/editor/internal/db"
 	"github.com/edit4i/editor/internal/service"
@@ -40,7 +41,6 @@ func (a *App) startup(ctx context.Context) {
 	// Initialize services
 	a.projects = service.NewProjectsService(dbConn)
 	a.files = service.NewFileService()
-	a.git = service.NewGitService()
 
 	config, err := service.NewConfigService()
 	if err != nil {
@@ -48,6 +48,11 @@ func (a *App) startup(ctx context.Context) {
 	}
 	a.config = config
 
+	a.git = service.NewGitService(config.GetConfig().Git, func(event string, data interface{}) {
+		// Emit git events to frontend
+		runtime.EventsEmit(a.ctx, event, data)
+	})
+
 	// Initialize terminal service with event handler
 	a.terminalService = service.NewTerminalService(func(id string, event *terminal.Event) {
 		// Emit terminal events to frontend
@@ -55,6 +60,13 @@ func (a *App) startup(ctx context.Context) {
 	})
 }
 
+// shutdown is called when the app is closing
+func (a *App) shutdown(ctx context.Context) {
+	if a.git != nil {
+		a.git.Close()
+	}
+}
+
 // GetRecentProjects returns the list of recent projects
 func (a *App) GetRecentProjects() ([]db.Project, error) {
 	return a.projects.GetRecentProjects(4)
@@ -85,6 +97,20 @@ func (a *App) AddProject(name, path string) (*db.Project, error) {
 	return a.projects.AddProject(name, path)
 }
 
+// CloneRepository clones a repository, reporting progress with the given identifier, and adds it as a project
+func (a *App) CloneRepository(id string, opts service.CloneOptions) (*db.Project, error) {
+	path, err := a.git.Clone(id, opts)
+	if err != nil {
+		return nil, err
+	}
+	return a.projects.AddProject(filepath.Base(path), path)
+}
+
+// CancelClone stops a running clone
+func (a *App) CancelClone(id string) error {
+	return a.git.CancelClone(id)
+}
+
 // GetProjectFiles returns the file tree for a project
 func (a *App) GetProjectFiles(projectPath string) (*service.FileNode, error) {
 	return a.files.GetProjectFiles(projectPath)
@@ -190,6 +216,16 @@ func (a *App) GetGitStatus(projectPath string) ([]service.FileStatus, error) {
 	return a.git.GetStatus(projectPath)
 }
 
+// GetGitStatusEntries returns the index and working tree state of every changed path
+func (a *App) GetGitStatusEntries(projectPath string, opts service.StatusOptions) ([]service.StatusEntry, error) {
+	return a.git.GetStatusEntries(projectPath, opts)
+}
+
+// CloseGitRepository stops watching the repository of a project that was closed
+func (a *App) CloseGitRepository(projectPath string) {
+	a.git.CloseRepository(projectPath)
+}
+
 // StageFile adds a file to the staging area
 func (a *App) StageFile(projectPath string, file string) error {
 	return a.git.StageFile(projectPath, file)
@@ -205,9 +241,104 @@ func (a *App) DiscardChanges(projectPath string, file string) error {
 	return a.git.DiscardChanges(projectPath, file)
 }
 
+// DiscardAllChanges reverts every tracked file to its staged version, optionally removing untracked files
+func (a *App) DiscardAllChanges(projectPath string, includeUntracked bool) error {
+	return a.git.DiscardAllChanges(projectPath, includeUntracked)
+}
+
+// DiscardStagedChanges reverts files with staged changes to HEAD in the index and the working tree
+func (a *App) DiscardStagedChanges(projectPath string, files []string) error {
+	return a.git.DiscardStagedChanges(projectPath, files)
+}
+
+// RestorePath restores a file or directory from a revision or from the index
+func (a *App) RestorePath(projectPath string, path string, opts service.RestoreOptions) error {
+	return a.git.RestorePath(projectPath, path, opts)
+}
+
+// PreviewReset returns what resetting the current branch to a revision will change
+func (a *App) PreviewReset(projectPath string, revision string, mode string) (*service.ResetPreview, error) {
+	return a.git.PreviewReset(projectPath, revision, mode)
+}
+
+// Reset moves the current branch to a revision with a soft, mixed or hard reset
+func (a *App) Reset(projectPath string, revision string, mode string) error {
+	return a.git.Reset(projectPath, revision, mode)
+}
+
+// ExportPatches writes commits as format-patch files and returns their paths
+func (a *App) ExportPatches(projectPath string, opts service.PatchExportOptions) ([]string, error) {
+	return a.git.ExportPatches(projectPath, opts)
+}
+
+// GetDiffPatch returns the staged or unstaged changes of files as a patch
+func (a *App) GetDiffPatch(projectPath string, opts service.DiffPatchOptions) (string, error) {
+	return a.git.GetDiffPatch(projectPath, opts)
+}
+
+// ExportDiffPatch returns uncommitted changes as a format-patch mbox, writing it to output when set
+func (a *App) ExportDiffPatch(projectPath string, opts service.DiffPatchOptions, output string) (string, error) {
+	return a.git.ExportDiffPatch(projectPath, opts, output)
+}
+
+// ApplyPatch applies a patch or mbox file to the working tree or the index
+func (a *App) ApplyPatch(projectPath string, opts service.PatchApplyOptions) (*service.PatchApplyResult, error) {
+	return a.git.ApplyPatch(projectPath, opts)
+}
+
+// ListRemotes returns the remotes of a repository with their URLs
+func (a *App) ListRemotes(projectPath string) ([]service.RemoteInfo, error) {
+	return a.git.ListRemotes(projectPath)
+}
+
+// AddRemote adds a remote to a repository
+func (a *App) AddRemote(projectPath string, name string, url string) error {
+	return a.git.AddRemote(projectPath, name, url)
+}
+
+// RenameRemote renames a remote
+func (a *App) RenameRemote(projectPath string, oldName string, newName string) error {
+	return a.git.RenameRemote(projectPath, oldName, newName)
+}
+
+// RemoveRemote removes a remote
+func (a *App) RemoveRemote(projectPath string, name string) error {
+	return a.git.RemoveRemote(projectPath, name)
+}
+
+// SetRemoteURL sets the fetch URL of a remote, or its push URL
+func (a *App) SetRemoteURL(projectPath string, name string, url string, push bool) error {
+	return a.git.SetRemoteURL(projectPath, name, url, push)
+}
+
+// GetUpstream returns the upstream of a local branch
+func (a *App) GetUpstream(projectPath string, branch string) (*service.BranchUpstream, error) {
+	return a.git.GetUpstream(projectPath, branch)
+}
+
+// SetUpstream sets or removes the upstream of a local branch
+func (a *App) SetUpstream(projectPath string, branch string, remote string, remoteBranch string) error {
+	return a.git.SetUpstream(projectPath, branch, remote, remoteBranch)
+}
+
+// CheckIgnore tells whether paths are ignored and which rule decides it
+func (a *App) CheckIgnore(projectPath string, paths []string) ([]service.IgnoreCheck, error) {
+	return a.git.CheckIgnore(projectPath, paths)
+}
+
+// AddIgnorePattern appends a pattern to the project .gitignore, info/exclude or the global excludes file
+func (a *App) AddIgnorePattern(projectPath string, pattern string, file string) (string, error) {
+	return a.git.AddIgnorePattern(projectPath, pattern, file)
+}
+
+// IgnorePath adds a rule ignoring a single file or directory to an ignore file
+func (a *App) IgnorePath(projectPath string, path string, file string) (string, error) {
+	return a.git.IgnorePath(projectPath, path, file)
+}
+
 // Commit creates a new commit with the staged changes
-func (a *App) Commit(projectPath string, message string) error {
-	return a.git.Commit(projectPath, message)
+func (a *App) Commit(projectPath string, message string, opts service.CommitOptions) (*service.CommitResult, error) {
+	return a.git.Commit(projectPath, message, opts)
 }
 
 // ListBranches returns a list of all branches in the repository
@@ -220,6 +351,11 @@ func (a *App) GetCurrentBranch(projectPath string) (string, error) {
 	return a.git.GetCurrentBranch(projectPath)
 }
 
+// Checkout switches to a branch or detaches HEAD at another revision
+func (a *App) Checkout(projectPath string, revision string) error {
+	return a.git.Checkout(projectPath, revision)
+}
+
 // ListCommits returns a list of commits based on the provided filters
 func (a *App) ListCommits(projectPath string, filter service.CommitFilter) ([]service.CommitInfo, error) {
 	return a.git.ListCommits(projectPath, filter)
@@ -245,6 +381,16 @@ func (a *App) SearchCommits(projectPath string, query string, limit int) ([]serv
 	return a.git.SearchCommits(projectPath, query, limit)
 }
 
+// SearchCommitHistory searches commits by message, content, paths, people, dates and merges, streaming results with the given identifier
+func (a *App) SearchCommitHistory(projectPath string, id string, opts service.CommitSearchOptions) ([]service.CommitInfo, error) {
+	return a.git.SearchCommitHistory(projectPath, id, opts)
+}
+
+// CancelCommitSearch stops a running commit search
+func (a *App) CancelCommitSearch(id string) error {
+	return a.git.CancelCommitSearch(id)
+}
+
 // GetHeadCommit returns the head commit of the repository
 func (a *App) GetHeadCommit(projectPath string) (*service.CommitInfo, error) {
 	return a.git.GetHeadCommit(projectPath)
@@ -254,3 +400,233 @@ func (a *App) GetHeadCommit(projectPath string) (*service.CommitInfo, error) {
 func (a *App) GetFileDiff(projectPath string, filePath string, staged bool) (*service.FileDiff, error) {
 	return a.git.GetFileDiff(projectPath, filePath, staged)
 }
+
+// GetFileAttributes returns the .gitattributes settings of a file
+func (a *App) GetFileAttributes(projectPath string, filePath string) (*service.FileAttributes, error) {
+	return a.git.GetFileAttributes(projectPath, filePath)
+}
+
+// ListStashes returns the stash entries of the repository
+func (a *App) ListStashes(projectPath string) ([]service.StashEntry, error) {
+	return a.git.ListStashes(projectPath)
+}
+
+// SaveStash stashes the local changes of the repository
+func (a *App) SaveStash(projectPath string, opts service.StashOptions) error {
+	return a.git.SaveStash(projectPath, opts)
+}
+
+// ApplyStash applies a stash entry, keeping it in the stash list
+func (a *App) ApplyStash(projectPath string, index int) error {
+	return a.git.ApplyStash(projectPath, index)
+}
+
+// PopStash applies a stash entry and removes it from the stash list
+func (a *App) PopStash(projectPath string, index int) error {
+	return a.git.PopStash(projectPath, index)
+}
+
+// DropStash removes a stash entry from the stash list
+func (a *App) DropStash(projectPath string, index int) error {
+	return a.git.DropStash(projectPath, index)
+}
+
+// GetStashDiff returns the per-file diffs recorded in a stash entry
+func (a *App) GetStashDiff(projectPath string, index int) ([]service.FileDiff, error) {
+	return a.git.GetStashDiff(projectPath, index)
+}
+
+// GetCommitDetails returns a commit's metadata and the files it changed compared to one of its parents
+func (a *App) GetCommitDetails(projectPath string, hash string, parentIndex int) (*service.CommitDetails, error) {
+	return a.git.GetCommitDetails(projectPath, hash, parentIndex)
+}
+
+// CompareRevisions returns the files changed between two revisions
+func (a *App) CompareRevisions(projectPath string, opts service.CompareOptions) (*service.CompareResult, error) {
+	return a.git.CompareRevisions(projectPath, opts)
+}
+
+// GetCompareFileDiff returns the diff of a single file between two revisions
+func (a *App) GetCompareFileDiff(projectPath string, opts service.CompareOptions, filePath string) (*service.FileDiff, error) {
+	return a.git.GetCompareFileDiff(projectPath, opts, filePath)
+}
+
+// GetBlame returns per-line blame information for a file at a revision, or for the working copy if revision is empty
+func (a *App) GetBlame(projectPath string, filePath string, revision string) (*service.BlameResult, error) {
+	return a.git.GetBlame(projectPath, filePath, revision)
+}
+
+// GetFileAtRevision returns the content of a file as stored in a revision
+func (a *App) GetFileAtRevision(projectPath string, revision string, filePath string) (string, error) {
+	return a.git.GetFileAtRevision(projectPath, revision, filePath)
+}
+
+// MergeBranch merges a branch into the current one
+func (a *App) MergeBranch(projectPath string, branch string, opts service.MergeOptions) (*service.MergeResult, error) {
+	return a.git.MergeBranch(projectPath, branch, opts)
+}
+
+// AbortMerge aborts the merge in progress
+func (a *App) AbortMerge(projectPath string) error {
+	return a.git.AbortMerge(projectPath)
+}
+
+// GetMergeState returns the state of the merge in progress, if any
+func (a *App) GetMergeState(projectPath string) (*service.MergeState, error) {
+	return a.git.GetMergeState(projectPath)
+}
+
+// GetConflict returns the versions and conflict regions of a conflicted file
+func (a *App) GetConflict(projectPath string, file string) (*service.ConflictInfo, error) {
+	return a.git.GetConflict(projectPath, file)
+}
+
+// ResolveConflict resolves a conflicted file and marks it as resolved
+func (a *App) ResolveConflict(projectPath string, file string, resolution service.ConflictResolution) error {
+	return a.git.ResolveConflict(projectPath, file, resolution)
+}
+
+// CompleteMerge creates the merge commit once all conflicts are resolved
+func (a *App) CompleteMerge(projectPath string, message string) error {
+	return a.git.CompleteMerge(projectPath, message)
+}
+
+// GetRebasePlan returns an editable interactive rebase plan for the commits after base
+func (a *App) GetRebasePlan(projectPath string, base string) (*service.RebasePlan, error) {
+	return a.git.GetRebasePlan(projectPath, base)
+}
+
+// StartRebase starts an interactive rebase executing the plan
+func (a *App) StartRebase(projectPath string, plan service.RebasePlan) (*service.RebaseState, error) {
+	return a.git.StartRebase(projectPath, plan)
+}
+
+// ContinueRebase continues a stopped rebase
+func (a *App) ContinueRebase(projectPath string) (*service.RebaseState, error) {
+	return a.git.ContinueRebase(projectPath)
+}
+
+// SkipRebase skips the step the rebase stopped at
+func (a *App) SkipRebase(projectPath string) (*service.RebaseState, error) {
+	return a.git.SkipRebase(projectPath)
+}
+
+// AbortRebase aborts the rebase in progress and restores the repository
+func (a *App) AbortRebase(projectPath string) error {
+	return a.git.AbortRebase(projectPath)
+}
+
+// GetRebaseState returns the state of the rebase in progress, if any
+func (a *App) GetRebaseState(projectPath string) (*service.RebaseState, error) {
+	return a.git.GetRebaseState(projectPath)
+}
+
+// CherryPick applies the given commits on top of the current branch
+func (a *App) CherryPick(projectPath string, commits []string, opts service.PickOptions) (*service.PickState, error) {
+	return a.git.CherryPick(projectPath, commits, opts)
+}
+
+// Revert creates commits reverting the given commits
+func (a *App) Revert(projectPath string, commits []string, opts service.PickOptions) (*service.PickState, error) {
+	return a.git.Revert(projectPath, commits, opts)
+}
+
+// ContinuePick continues a stopped cherry-pick or revert
+func (a *App) ContinuePick(projectPath string) (*service.PickState, error) {
+	return a.git.ContinuePick(projectPath)
+}
+
+// AbortPick aborts the cherry-pick or revert in progress
+func (a *App) AbortPick(projectPath string) error {
+	return a.git.AbortPick(projectPath)
+}
+
+// GetPickState returns the state of the cherry-pick or revert in progress, if any
+func (a *App) GetPickState(projectPath string) (*service.PickState, error) {
+	return a.git.GetPickState(projectPath)
+}
+
+// ListTags returns the tags of the repository
+func (a *App) ListTags(projectPath string) ([]service.TagInfo, error) {
+	return a.git.ListTags(projectPath)
+}
+
+// CreateTag creates a lightweight or annotated tag
+func (a *App) CreateTag(projectPath string, opts service.TagOptions) error {
+	return a.git.CreateTag(projectPath, opts)
+}
+
+// DeleteTag deletes a local tag
+func (a *App) DeleteTag(projectPath string, name string) error {
+	return a.git.DeleteTag(projectPath, name)
+}
+
+// PushTags pushes tags to a remote
+func (a *App) PushTags(projectPath string, remote string, names []string) error {
+	return a.git.PushTags(projectPath, remote, names)
+}
+
+// GetCommitConvention returns the commit message convention of a project
+func (a *App) GetCommitConvention(projectPath string) (*service.CommitConvention, error) {
+	return a.git.GetCommitConvention(projectPath)
+}
+
+// ValidateCommitMessage checks a commit message against the project convention
+func (a *App) ValidateCommitMessage(projectPath string, message string) ([]service.CommitMessageIssue, error) {
+	return a.git.ValidateCommitMessage(projectPath, message)
+}
+
+// GetCommitTemplate returns the message template that pre-fills the commit message box
+func (a *App) GetCommitTemplate(projectPath string) (string, error) {
+	return a.git.GetCommitTemplate(projectPath)
+}
+
+// ListSubmodules returns the submodules of a repository with their checkout state
+func (a *App) ListSubmodules(projectPath string) ([]service.SubmoduleInfo, error) {
+	return a.git.ListSubmodules(projectPath)
+}
+
+// InitSubmodules registers submodules so they can be updated
+func (a *App) InitSubmodules(projectPath string, paths []string) error {
+	return a.git.InitSubmodules(projectPath, paths)
+}
+
+// UpdateSubmodules clones missing submodules and checks out their recorded commits
+func (a *App) UpdateSubmodules(projectPath string, opts service.SubmoduleUpdateOptions) error {
+	return a.git.UpdateSubmodules(projectPath, opts)
+}
+
+// ListWorktrees returns the main and linked working trees of a repository
+func (a *App) ListWorktrees(projectPath string) ([]service.WorktreeInfo, error) {
+	return a.git.ListWorktrees(projectPath)
+}
+
+// AddWorktree creates a linked working tree
+func (a *App) AddWorktree(projectPath string, opts service.WorktreeOptions) (*service.WorktreeInfo, error) {
+	return a.git.AddWorktree(projectPath, opts)
+}
+
+// RemoveWorktree removes a linked working tree
+func (a *App) RemoveWorktree(projectPath string, path string, force bool) error {
+	return a.git.RemoveWorktree(projectPath, path, force)
+}
+
+// GetReflog returns the reflog of HEAD or of a branch
+func (a *App) GetReflog(projectPath string, ref string, limit int) ([]service.ReflogEntry, error) {
+	return a.git.GetReflog(projectPath, ref, limit)
+}
+
+// ListUndoOperations returns the operations that can be undone, most recent first
+func (a *App) ListUndoOperations(projectPath string) ([]service.UndoEntry, error) {
+	return a.git.ListUndoOperations(projectPath)
+}
+
+// PreviewUndo describes what undoing an operation will change
+func (a *App) PreviewUndo(projectPath string, id int) (*service.UndoPreview, error) {
+	return a.git.PreviewUndo(projectPath, id)
+}
+
+// UndoOperation restores the state from before an operation
+func (a *App) UndoOperation(projectPath string, id int) error {
+	return a.git.UndoOperation(projectPath, id)
+}
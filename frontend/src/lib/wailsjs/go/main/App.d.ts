@@ -3,46 +3,136 @@
 import {db} from '../models';
 import {service} from '../models';
 
+export function AbortMerge(arg1:string):Promise<void>;
+
+export function AbortPick(arg1:string):Promise<void>;
+
+export function AbortRebase(arg1:string):Promise<void>;
+
+export function AddIgnorePattern(arg1:string,arg2:string,arg3:string):Promise<string>;
+
 export function AddProject(arg1:string,arg2:string):Promise<db.Project>;
 
-export function Commit(arg1:string,arg2:string):Promise<void>;
+export function AddRemote(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function AddWorktree(arg1:string,arg2:service.WorktreeOptions):Promise<service.WorktreeInfo>;
+
+export function ApplyPatch(arg1:string,arg2:service.PatchApplyOptions):Promise<service.PatchApplyResult>;
+
+export function ApplyStash(arg1:string,arg2:number):Promise<void>;
+
+export function CancelClone(arg1:string):Promise<void>;
+
+export function CancelCommitSearch(arg1:string):Promise<void>;
+
+export function CheckIgnore(arg1:string,arg2:Array<string>):Promise<Array<service.IgnoreCheck>>;
+
+export function Checkout(arg1:string,arg2:string):Promise<void>;
+
+export function CherryPick(arg1:string,arg2:Array<string>,arg3:service.PickOptions):Promise<service.PickState>;
+
+export function CloneRepository(arg1:string,arg2:service.CloneOptions):Promise<db.Project>;
+
+export function CloseGitRepository(arg1:string):Promise<void>;
+
+export function Commit(arg1:string,arg2:string,arg3:service.CommitOptions):Promise<service.CommitResult>;
+
+export function CompareRevisions(arg1:string,arg2:service.CompareOptions):Promise<service.CompareResult>;
+
+export function CompleteMerge(arg1:string,arg2:string):Promise<void>;
+
+export function ContinuePick(arg1:string):Promise<service.PickState>;
+
+export function ContinueRebase(arg1:string):Promise<service.RebaseState>;
 
 export function CreateDirectory(arg1:string):Promise<void>;
 
 export function CreateFile(arg1:string):Promise<void>;
 
+export function CreateTag(arg1:string,arg2:service.TagOptions):Promise<void>;
+
 export function CreateTerminal(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function DeleteFile(arg1:string):Promise<void>;
 
+export function DeleteTag(arg1:string,arg2:string):Promise<void>;
+
 export function DestroyTerminal(arg1:string):Promise<void>;
 
+export function DiscardAllChanges(arg1:string,arg2:boolean):Promise<void>;
+
 export function DiscardChanges(arg1:string,arg2:string):Promise<void>;
 
+export function DiscardStagedChanges(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function DropStash(arg1:string,arg2:number):Promise<void>;
+
+export function ExportDiffPatch(arg1:string,arg2:service.DiffPatchOptions,arg3:string):Promise<string>;
+
+export function ExportPatches(arg1:string,arg2:service.PatchExportOptions):Promise<Array<string>>;
+
 export function GetAvailableShells():Promise<Array<string>>;
 
+export function GetBlame(arg1:string,arg2:string,arg3:string):Promise<service.BlameResult>;
+
+export function GetCommitConvention(arg1:string):Promise<service.CommitConvention>;
+
+export function GetCommitDetails(arg1:string,arg2:string,arg3:number):Promise<service.CommitDetails>;
+
+export function GetCommitTemplate(arg1:string):Promise<string>;
+
+export function GetCompareFileDiff(arg1:string,arg2:service.CompareOptions,arg3:string):Promise<service.FileDiff>;
+
+export function GetConflict(arg1:string,arg2:string):Promise<service.ConflictInfo>;
+
 export function GetCurrentBranch(arg1:string):Promise<string>;
 
+export function GetDiffPatch(arg1:string,arg2:service.DiffPatchOptions):Promise<string>;
+
 export function GetEditorConfig():Promise<service.EditorConfig>;
 
+export function GetFileAtRevision(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function GetFileAttributes(arg1:string,arg2:string):Promise<service.FileAttributes>;
+
 export function GetFileContent(arg1:string):Promise<string>;
 
 export function GetFileDiff(arg1:string,arg2:string,arg3:boolean):Promise<service.FileDiff>;
 
 export function GetGitStatus(arg1:string):Promise<Array<service.FileStatus>>;
 
+export function GetGitStatusEntries(arg1:string,arg2:service.StatusOptions):Promise<Array<service.StatusEntry>>;
+
 export function GetHeadCommit(arg1:string):Promise<service.CommitInfo>;
 
+export function GetMergeState(arg1:string):Promise<service.MergeState>;
+
+export function GetPickState(arg1:string):Promise<service.PickState>;
+
 export function GetProjectFiles(arg1:string):Promise<service.FileNode>;
 
+export function GetRebasePlan(arg1:string,arg2:string):Promise<service.RebasePlan>;
+
+export function GetRebaseState(arg1:string):Promise<service.RebaseState>;
+
 export function GetRecentProjects():Promise<Array<db.Project>>;
 
+export function GetReflog(arg1:string,arg2:string,arg3:number):Promise<Array<service.ReflogEntry>>;
+
+export function GetStashDiff(arg1:string,arg2:number):Promise<Array<service.FileDiff>>;
+
+export function GetUpstream(arg1:string,arg2:string):Promise<service.BranchUpstream>;
+
 export function Greet(arg1:string):Promise<string>;
 
 export function HandleInput(arg1:string,arg2:Array<number>):Promise<void>;
 
+export function IgnorePath(arg1:string,arg2:string,arg3:string):Promise<string>;
+
 export function InitGitRepository(arg1:string):Promise<void>;
 
+export function InitSubmodules(arg1:string,arg2:Array<string>):Promise<void>;
+
 export function IsGitRepository(arg1:string):Promise<boolean>;
 
 export function ListBranches(arg1:string):Promise<Array<service.BranchInfo>>;
@@ -55,22 +145,76 @@ export function ListCommitsByAuthor(arg1:string,arg2:string,arg3:number):Promise
 
 export function ListCommitsByBranch(arg1:string,arg2:string,arg3:number):Promise<Array<service.CommitInfo>>;
 
+export function ListRemotes(arg1:string):Promise<Array<service.RemoteInfo>>;
+
+export function ListStashes(arg1:string):Promise<Array<service.StashEntry>>;
+
+export function ListSubmodules(arg1:string):Promise<Array<service.SubmoduleInfo>>;
+
+export function ListTags(arg1:string):Promise<Array<service.TagInfo>>;
+
+export function ListUndoOperations(arg1:string):Promise<Array<service.UndoEntry>>;
+
+export function ListWorktrees(arg1:string):Promise<Array<service.WorktreeInfo>>;
+
 export function LoadDirectoryContents(arg1:string):Promise<service.FileNode>;
 
+export function MergeBranch(arg1:string,arg2:string,arg3:service.MergeOptions):Promise<service.MergeResult>;
+
 export function OpenConfigFile():Promise<string>;
 
 export function OpenProjectFolder():Promise<string>;
 
+export function PopStash(arg1:string,arg2:number):Promise<void>;
+
+export function PreviewReset(arg1:string,arg2:string,arg3:string):Promise<service.ResetPreview>;
+
+export function PreviewUndo(arg1:string,arg2:number):Promise<service.UndoPreview>;
+
+export function PushTags(arg1:string,arg2:string,arg3:Array<string>):Promise<void>;
+
+export function RemoveRemote(arg1:string,arg2:string):Promise<void>;
+
+export function RemoveWorktree(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
 export function RenameFile(arg1:string,arg2:string):Promise<void>;
 
+export function RenameRemote(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function Reset(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function ResizeTerminal(arg1:string,arg2:number,arg3:number):Promise<void>;
 
+export function ResolveConflict(arg1:string,arg2:string,arg3:service.ConflictResolution):Promise<void>;
+
+export function RestorePath(arg1:string,arg2:string,arg3:service.RestoreOptions):Promise<void>;
+
+export function Revert(arg1:string,arg2:Array<string>,arg3:service.PickOptions):Promise<service.PickState>;
+
 export function SaveFile(arg1:string,arg2:string):Promise<void>;
 
+export function SaveStash(arg1:string,arg2:service.StashOptions):Promise<void>;
+
+export function SearchCommitHistory(arg1:string,arg2:string,arg3:service.CommitSearchOptions):Promise<Array<service.CommitInfo>>;
+
 export function SearchCommits(arg1:string,arg2:string,arg3:number):Promise<Array<service.CommitInfo>>;
 
 export function SearchFiles(arg1:string,arg2:string):Promise<Array<service.FileNode>>;
 
+export function SetRemoteURL(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<void>;
+
+export function SetUpstream(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function SkipRebase(arg1:string):Promise<service.RebaseState>;
+
 export function StageFile(arg1:string,arg2:string):Promise<void>;
 
+export function StartRebase(arg1:string,arg2:service.RebasePlan):Promise<service.RebaseState>;
+
+export function UndoOperation(arg1:string,arg2:number):Promise<void>;
+
 export function UnstageFile(arg1:string,arg2:string):Promise<void>;
+
+export function UpdateSubmodules(arg1:string,arg2:service.SubmoduleUpdateOptions):Promise<void>;
+
+export function ValidateCommitMessage(arg1:string,arg2:string):Promise<Array<service.CommitMessageIssue>>;
@@ -2,12 +2,88 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AbortMerge(arg1) {
+  return window['go']['main']['App']['AbortMerge'](arg1);
+}
+
+export function AbortPick(arg1) {
+  return window['go']['main']['App']['AbortPick'](arg1);
+}
+
+export function AbortRebase(arg1) {
+  return window['go']['main']['App']['AbortRebase'](arg1);
+}
+
+export function AddIgnorePattern(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddIgnorePattern'](arg1, arg2, arg3);
+}
+
 export function AddProject(arg1, arg2) {
   return window['go']['main']['App']['AddProject'](arg1, arg2);
 }
 
-export function Commit(arg1, arg2) {
-  return window['go']['main']['App']['Commit'](arg1, arg2);
+export function AddRemote(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddRemote'](arg1, arg2, arg3);
+}
+
+export function AddWorktree(arg1, arg2) {
+  return window['go']['main']['App']['AddWorktree'](arg1, arg2);
+}
+
+export function ApplyPatch(arg1, arg2) {
+  return window['go']['main']['App']['ApplyPatch'](arg1, arg2);
+}
+
+export function ApplyStash(arg1, arg2) {
+  return window['go']['main']['App']['ApplyStash'](arg1, arg2);
+}
+
+export function CancelClone(arg1) {
+  return window['go']['main']['App']['CancelClone'](arg1);
+}
+
+export function CancelCommitSearch(arg1) {
+  return window['go']['main']['App']['CancelCommitSearch'](arg1);
+}
+
+export function CheckIgnore(arg1, arg2) {
+  return window['go']['main']['App']['CheckIgnore'](arg1, arg2);
+}
+
+export function Checkout(arg1, arg2) {
+  return window['go']['main']['App']['Checkout'](arg1, arg2);
+}
+
+export function CherryPick(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CherryPick'](arg1, arg2, arg3);
+}
+
+export function CloneRepository(arg1, arg2) {
+  return window['go']['main']['App']['CloneRepository'](arg1, arg2);
+}
+
+export function CloseGitRepository(arg1) {
+  return window['go']['main']['App']['CloseGitRepository'](arg1);
+}
+
+export function Commit(arg1, arg2, arg3) {
+  return window['go']['main']['App']['Commit'](arg1, arg2, arg3);
+}
+
+export function CompareRevisions(arg1, arg2) {
+  return window['go']['main']['App']['CompareRevisions'](arg1, arg2);
+}
+
+export function CompleteMerge(arg1, arg2) {
+  return window['go']['main']['App']['CompleteMerge'](arg1, arg2);
+}
+
+export function ContinuePick(arg1) {
+  return window['go']['main']['App']['ContinuePick'](arg1);
+}
+
+export function ContinueRebase(arg1) {
+  return window['go']['main']['App']['ContinueRebase'](arg1);
 }
 
 export function CreateDirectory(arg1) {
@@ -18,6 +94,10 @@ export function CreateFile(arg1) {
   return window['go']['main']['App']['CreateFile'](arg1);
 }
 
+export function CreateTag(arg1, arg2) {
+  return window['go']['main']['App']['CreateTag'](arg1, arg2);
+}
+
 export function CreateTerminal(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreateTerminal'](arg1, arg2, arg3);
 }
@@ -26,26 +106,86 @@ export function DeleteFile(arg1) {
   return window['go']['main']['App']['DeleteFile'](arg1);
 }
 
+export function DeleteTag(arg1, arg2) {
+  return window['go']['main']['App']['DeleteTag'](arg1, arg2);
+}
+
 export function DestroyTerminal(arg1) {
   return window['go']['main']['App']['DestroyTerminal'](arg1);
 }
 
+export function DiscardAllChanges(arg1, arg2) {
+  return window['go']['main']['App']['DiscardAllChanges'](arg1, arg2);
+}
+
 export function DiscardChanges(arg1, arg2) {
   return window['go']['main']['App']['DiscardChanges'](arg1, arg2);
 }
 
+export function DiscardStagedChanges(arg1, arg2) {
+  return window['go']['main']['App']['DiscardStagedChanges'](arg1, arg2);
+}
+
+export function DropStash(arg1, arg2) {
+  return window['go']['main']['App']['DropStash'](arg1, arg2);
+}
+
+export function ExportDiffPatch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportDiffPatch'](arg1, arg2, arg3);
+}
+
+export function ExportPatches(arg1, arg2) {
+  return window['go']['main']['App']['ExportPatches'](arg1, arg2);
+}
+
 export function GetAvailableShells() {
   return window['go']['main']['App']['GetAvailableShells']();
 }
 
+export function GetBlame(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetBlame'](arg1, arg2, arg3);
+}
+
+export function GetCommitConvention(arg1) {
+  return window['go']['main']['App']['GetCommitConvention'](arg1);
+}
+
+export function GetCommitDetails(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetCommitDetails'](arg1, arg2, arg3);
+}
+
+export function GetCommitTemplate(arg1) {
+  return window['go']['main']['App']['GetCommitTemplate'](arg1);
+}
+
+export function GetCompareFileDiff(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetCompareFileDiff'](arg1, arg2, arg3);
+}
+
+export function GetConflict(arg1, arg2) {
+  return window['go']['main']['App']['GetConflict'](arg1, arg2);
+}
+
 export function GetCurrentBranch(arg1) {
   return window['go']['main']['App']['GetCurrentBranch'](arg1);
 }
 
+export function GetDiffPatch(arg1, arg2) {
+  return window['go']['main']['App']['GetDiffPatch'](arg1, arg2);
+}
+
 export function GetEditorConfig() {
   return window['go']['main']['App']['GetEditorConfig']();
 }
 
+export function GetFileAtRevision(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetFileAtRevision'](arg1, arg2, arg3);
+}
+
+export function GetFileAttributes(arg1, arg2) {
+  return window['go']['main']['App']['GetFileAttributes'](arg1, arg2);
+}
+
 export function GetFileContent(arg1) {
   return window['go']['main']['App']['GetFileContent'](arg1);
 }
@@ -58,18 +198,50 @@ export function GetGitStatus(arg1) {
   return window['go']['main']['App']['GetGitStatus'](arg1);
 }
 
+export function GetGitStatusEntries(arg1, arg2) {
+  return window['go']['main']['App']['GetGitStatusEntries'](arg1, arg2);
+}
+
 export function GetHeadCommit(arg1) {
   return window['go']['main']['App']['GetHeadCommit'](arg1);
 }
 
+export function GetMergeState(arg1) {
+  return window['go']['main']['App']['GetMergeState'](arg1);
+}
+
+export function GetPickState(arg1) {
+  return window['go']['main']['App']['GetPickState'](arg1);
+}
+
 export function GetProjectFiles(arg1) {
   return window['go']['main']['App']['GetProjectFiles'](arg1);
 }
 
+export function GetRebasePlan(arg1, arg2) {
+  return window['go']['main']['App']['GetRebasePlan'](arg1, arg2);
+}
+
+export function GetRebaseState(arg1) {
+  return window['go']['main']['App']['GetRebaseState'](arg1);
+}
+
 export function GetRecentProjects() {
   return window['go']['main']['App']['GetRecentProjects']();
 }
 
+export function GetReflog(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetReflog'](arg1, arg2, arg3);
+}
+
+export function GetStashDiff(arg1, arg2) {
+  return window['go']['main']['App']['GetStashDiff'](arg1, arg2);
+}
+
+export function GetUpstream(arg1, arg2) {
+  return window['go']['main']['App']['GetUpstream'](arg1, arg2);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
@@ -78,10 +250,18 @@ export function HandleInput(arg1, arg2) {
   return window['go']['main']['App']['HandleInput'](arg1, arg2);
 }
 
+export function IgnorePath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['IgnorePath'](arg1, arg2, arg3);
+}
+
 export function InitGitRepository(arg1) {
   return window['go']['main']['App']['InitGitRepository'](arg1);
 }
 
+export function InitSubmodules(arg1, arg2) {
+  return window['go']['main']['App']['InitSubmodules'](arg1, arg2);
+}
+
 export function IsGitRepository(arg1) {
   return window['go']['main']['App']['IsGitRepository'](arg1);
 }
@@ -106,10 +286,38 @@ export function ListCommitsByBranch(arg1, arg2, arg3) {
   return window['go']['main']['App']['ListCommitsByBranch'](arg1, arg2, arg3);
 }
 
+export function ListRemotes(arg1) {
+  return window['go']['main']['App']['ListRemotes'](arg1);
+}
+
+export function ListStashes(arg1) {
+  return window['go']['main']['App']['ListStashes'](arg1);
+}
+
+export function ListSubmodules(arg1) {
+  return window['go']['main']['App']['ListSubmodules'](arg1);
+}
+
+export function ListTags(arg1) {
+  return window['go']['main']['App']['ListTags'](arg1);
+}
+
+export function ListUndoOperations(arg1) {
+  return window['go']['main']['App']['ListUndoOperations'](arg1);
+}
+
+export function ListWorktrees(arg1) {
+  return window['go']['main']['App']['ListWorktrees'](arg1);
+}
+
 export function LoadDirectoryContents(arg1) {
   return window['go']['main']['App']['LoadDirectoryContents'](arg1);
 }
 
+export function MergeBranch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['MergeBranch'](arg1, arg2, arg3);
+}
+
 export function OpenConfigFile() {
   return window['go']['main']['App']['OpenConfigFile']();
 }
@@ -118,18 +326,70 @@ export function OpenProjectFolder() {
   return window['go']['main']['App']['OpenProjectFolder']();
 }
 
+export function PopStash(arg1, arg2) {
+  return window['go']['main']['App']['PopStash'](arg1, arg2);
+}
+
+export function PreviewReset(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewReset'](arg1, arg2, arg3);
+}
+
+export function PreviewUndo(arg1, arg2) {
+  return window['go']['main']['App']['PreviewUndo'](arg1, arg2);
+}
+
+export function PushTags(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PushTags'](arg1, arg2, arg3);
+}
+
+export function RemoveRemote(arg1, arg2) {
+  return window['go']['main']['App']['RemoveRemote'](arg1, arg2);
+}
+
+export function RemoveWorktree(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RemoveWorktree'](arg1, arg2, arg3);
+}
+
 export function RenameFile(arg1, arg2) {
   return window['go']['main']['App']['RenameFile'](arg1, arg2);
 }
 
+export function RenameRemote(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RenameRemote'](arg1, arg2, arg3);
+}
+
+export function Reset(arg1, arg2, arg3) {
+  return window['go']['main']['App']['Reset'](arg1, arg2, arg3);
+}
+
 export function ResizeTerminal(arg1, arg2, arg3) {
   return window['go']['main']['App']['ResizeTerminal'](arg1, arg2, arg3);
 }
 
+export function ResolveConflict(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ResolveConflict'](arg1, arg2, arg3);
+}
+
+export function RestorePath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RestorePath'](arg1, arg2, arg3);
+}
+
+export function Revert(arg1, arg2, arg3) {
+  return window['go']['main']['App']['Revert'](arg1, arg2, arg3);
+}
+
 export function SaveFile(arg1, arg2) {
   return window['go']['main']['App']['SaveFile'](arg1, arg2);
 }
 
+export function SaveStash(arg1, arg2) {
+  return window['go']['main']['App']['SaveStash'](arg1, arg2);
+}
+
+export function SearchCommitHistory(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SearchCommitHistory'](arg1, arg2, arg3);
+}
+
 export function SearchCommits(arg1, arg2, arg3) {
   return window['go']['main']['App']['SearchCommits'](arg1, arg2, arg3);
 }
@@ -138,10 +398,38 @@ export function SearchFiles(arg1, arg2) {
   return window['go']['main']['App']['SearchFiles'](arg1, arg2);
 }
 
+export function SetRemoteURL(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetRemoteURL'](arg1, arg2, arg3, arg4);
+}
+
+export function SetUpstream(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetUpstream'](arg1, arg2, arg3, arg4);
+}
+
+export function SkipRebase(arg1) {
+  return window['go']['main']['App']['SkipRebase'](arg1);
+}
+
 export function StageFile(arg1, arg2) {
   return window['go']['main']['App']['StageFile'](arg1, arg2);
 }
 
+export function StartRebase(arg1, arg2) {
+  return window['go']['main']['App']['StartRebase'](arg1, arg2);
+}
+
+export function UndoOperation(arg1, arg2) {
+  return window['go']['main']['App']['UndoOperation'](arg1, arg2);
+}
+
 export function UnstageFile(arg1, arg2) {
   return window['go']['main']['App']['UnstageFile'](arg1, arg2);
 }
+
+export function UpdateSubmodules(arg1, arg2) {
+  return window['go']['main']['App']['UpdateSubmodules'](arg1, arg2);
+}
+
+export function ValidateCommitMessage(arg1, arg2) {
+  return window['go']['main']['App']['ValidateCommitMessage'](arg1, arg2);
+}
@@ -45,50 +45,65 @@ export namespace db {
 
 export namespace service {
 	
-	export class BranchInfo {
-	    name: string;
-	    isRemote: boolean;
-	    isHead: boolean;
+	export class BlameLine {
+	    line: number;
+	    content: string;
+	    hash: string;
+	    author: string;
+	    authorEmail: string;
+	    // Go type: time
+	    date: any;
+	    summary: string;
+	    uncommitted: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new BranchInfo(source);
+	        return new BlameLine(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.isRemote = source["isRemote"];
-	        this.isHead = source["isHead"];
+	        this.line = source["line"];
+	        this.content = source["content"];
+	        this.hash = source["hash"];
+	        this.author = source["author"];
+	        this.authorEmail = source["authorEmail"];
+	        this.date = this.convertValues(source["date"], null);
+	        this.summary = source["summary"];
+	        this.uncommitted = source["uncommitted"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class CommitFilter {
-	    branch: string;
-	    startHash: string;
-	    limit: number;
-	    offset: number;
-	    offsetHash: string;
-	    author: string;
-	    searchQuery: string;
-	    // Go type: time
-	    startDate: any;
-	    // Go type: time
-	    endDate: any;
+	export class BlameResult {
+	    path: string;
+	    revision: string;
+	    lines: BlameLine[];
 	
 	    static createFrom(source: any = {}) {
-	        return new CommitFilter(source);
+	        return new BlameResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.branch = source["branch"];
-	        this.startHash = source["startHash"];
-	        this.limit = source["limit"];
-	        this.offset = source["offset"];
-	        this.offsetHash = source["offsetHash"];
-	        this.author = source["author"];
-	        this.searchQuery = source["searchQuery"];
-	        this.startDate = this.convertValues(source["startDate"], null);
-	        this.endDate = this.convertValues(source["endDate"], null);
+	        this.path = source["path"];
+	        this.revision = source["revision"];
+	        this.lines = this.convertValues(source["lines"], BlameLine);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -109,29 +124,116 @@ export namespace service {
 		    return a;
 		}
 	}
-	export class CommitInfo {
-	    hash: string;
-	    message: string;
-	    author: string;
-	    authorEmail: string;
-	    // Go type: time
-	    date: any;
-	    parentHashes: string[];
-	    hasMore: boolean;
+	export class BranchInfo {
+	    name: string;
+	    isRemote: boolean;
+	    isHead: boolean;
+	    upstream?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new CommitInfo(source);
+	        return new BranchInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.hash = source["hash"];
-	        this.message = source["message"];
-	        this.author = source["author"];
-	        this.authorEmail = source["authorEmail"];
-	        this.date = this.convertValues(source["date"], null);
-	        this.parentHashes = source["parentHashes"];
-	        this.hasMore = source["hasMore"];
+	        this.name = source["name"];
+	        this.isRemote = source["isRemote"];
+	        this.isHead = source["isHead"];
+	        this.upstream = source["upstream"];
+	    }
+	}
+	export class BranchUpstream {
+	    branch: string;
+	    remote: string;
+	    merge: string;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BranchUpstream(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.remote = source["remote"];
+	        this.merge = source["merge"];
+	        this.name = source["name"];
+	    }
+	}
+	export class CloneOptions {
+	    url: string;
+	    destination: string;
+	    branch: string;
+	    depth: number;
+	    recurseSubmodules: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CloneOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.destination = source["destination"];
+	        this.branch = source["branch"];
+	        this.depth = source["depth"];
+	        this.recurseSubmodules = source["recurseSubmodules"];
+	    }
+	}
+	export class CommitConvention {
+	    mode: string;
+	    conventional: boolean;
+	    types: string[];
+	    scopes: string[];
+	    requireScope: boolean;
+	    maxSubjectLength: number;
+	    maxBodyLineLength: number;
+	    requiredTrailers: {[key: string]: string};
+	    template: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitConvention(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.conventional = source["conventional"];
+	        this.types = source["types"];
+	        this.scopes = source["scopes"];
+	        this.requireScope = source["requireScope"];
+	        this.maxSubjectLength = source["maxSubjectLength"];
+	        this.maxBodyLineLength = source["maxBodyLineLength"];
+	        this.requiredTrailers = source["requiredTrailers"];
+	        this.template = source["template"];
+	    }
+	}
+	export class LFSPointer {
+	    oid: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LFSPointer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.oid = source["oid"];
+	        this.size = source["size"];
+	    }
+	}
+	export class LFSDiff {
+	    old?: LFSPointer;
+	    new?: LFSPointer;
+	
+	    static createFrom(source: any = {}) {
+	        return new LFSDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.old = this.convertValues(source["old"], LFSPointer);
+	        this.new = this.convertValues(source["new"], LFSPointer);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -168,22 +270,30 @@ export namespace service {
 	        this.modified = source["modified"];
 	    }
 	}
-	export class EditorConfig {
-	    // Go type: struct { Theme string "json:\"theme\" mapstructure:\"theme\""; FontSize int "json:\"fontSize\" mapstructure:\"fontSize\""; TabSize int "json:\"tabSize\" mapstructure:\"tabSize\""; WordWrap bool "json:\"wordWrap\" mapstructure:\"wordWrap\""; LineNumbers bool "json:\"lineNumbers\" mapstructure:\"lineNumbers\""; RelativeLines bool "json:\"relativeLines\" mapstructure:\"relativeLines\""; Minimap bool "json:\"minimap\" mapstructure:\"minimap\""; StickyScroll bool "json:\"stickyScroll\" mapstructure:\"stickyScroll\""; Vim struct { Enabled bool "json:\"enabled\" mapstructure:\"enabled\""; DefaultMode string "json:\"defaultMode\" mapstructure:\"defaultMode\"" } "json:\"vim\" mapstructure:\"vim\"" }
-	    editor: any;
-	    // Go type: struct { DefaultShell string "json:\"defaultShell\" mapstructure:\"defaultShell\""; FontSize int "json:\"fontSize\" mapstructure:\"fontSize\""; FontFamily string "json:\"fontFamily\" mapstructure:\"fontFamily\""; Theme struct { Background string "json:\"background\" mapstructure:\"background\""; Foreground string "json:\"foreground\" mapstructure:\"foreground\""; Cursor string "json:\"cursor\" mapstructure:\"cursor\""; SelectionBackground string "json:\"selectionBackground\" mapstructure:\"selectionBackground\""; SelectionForeground string "json:\"selectionForeground\" mapstructure:\"selectionForeground\"" } "json:\"theme\" mapstructure:\"theme\"" }
-	    terminal: any;
-	    keyboard: struct { CustomBindings map[string]service.;
+	export class FileDiff {
+	    path: string;
+	    content: string;
+	    stats: DiffStats;
+	    isBinary: boolean;
+	    status?: string;
+	    oldPath?: string;
+	    generated?: boolean;
+	    lfs?: LFSDiff;
 	
 	    static createFrom(source: any = {}) {
-	        return new EditorConfig(source);
+	        return new FileDiff(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.editor = this.convertValues(source["editor"], Object);
-	        this.terminal = this.convertValues(source["terminal"], Object);
-	        this.keyboard = this.convertValues(source["keyboard"], Object);
+	        this.path = source["path"];
+	        this.content = source["content"];
+	        this.stats = this.convertValues(source["stats"], DiffStats);
+	        this.isBinary = source["isBinary"];
+	        this.status = source["status"];
+	        this.oldPath = source["oldPath"];
+	        this.generated = source["generated"];
+	        this.lfs = this.convertValues(source["lfs"], LFSDiff);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -204,22 +314,40 @@ export namespace service {
 		    return a;
 		}
 	}
-	export class FileDiff {
-	    path: string;
-	    content: string;
+	export class CommitDetails {
+	    hash: string;
+	    message: string;
+	    author: string;
+	    authorEmail: string;
+	    // Go type: time
+	    authorDate: any;
+	    committer: string;
+	    committerEmail: string;
+	    // Go type: time
+	    committerDate: any;
+	    parentHashes: string[];
+	    comparedParent: string;
+	    files: FileDiff[];
 	    stats: DiffStats;
-	    isBinary: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileDiff(source);
+	        return new CommitDetails(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.content = source["content"];
+	        this.hash = source["hash"];
+	        this.message = source["message"];
+	        this.author = source["author"];
+	        this.authorEmail = source["authorEmail"];
+	        this.authorDate = this.convertValues(source["authorDate"], null);
+	        this.committer = source["committer"];
+	        this.committerEmail = source["committerEmail"];
+	        this.committerDate = this.convertValues(source["committerDate"], null);
+	        this.parentHashes = source["parentHashes"];
+	        this.comparedParent = source["comparedParent"];
+	        this.files = this.convertValues(source["files"], FileDiff);
 	        this.stats = this.convertValues(source["stats"], DiffStats);
-	        this.isBinary = source["isBinary"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -240,29 +368,40 @@ export namespace service {
 		    return a;
 		}
 	}
-	export class FileNode {
-	    name: string;
-	    path: string;
-	    type: string;
-	    size?: number;
+	export class CommitFilter {
+	    branch: string;
+	    startHash: string;
+	    limit: number;
+	    offset: number;
+	    offsetHash: string;
+	    author: string;
+	    searchQuery: string;
 	    // Go type: time
-	    lastModified: any;
-	    children?: FileNode[];
-	    isLoaded: boolean;
+	    startDate: any;
+	    // Go type: time
+	    endDate: any;
+	    path: string;
+	    followRenames: boolean;
+	    includeGraph: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileNode(source);
+	        return new CommitFilter(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
+	        this.branch = source["branch"];
+	        this.startHash = source["startHash"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	        this.offsetHash = source["offsetHash"];
+	        this.author = source["author"];
+	        this.searchQuery = source["searchQuery"];
+	        this.startDate = this.convertValues(source["startDate"], null);
+	        this.endDate = this.convertValues(source["endDate"], null);
 	        this.path = source["path"];
-	        this.type = source["type"];
-	        this.size = source["size"];
-	        this.lastModified = this.convertValues(source["lastModified"], null);
-	        this.children = this.convertValues(source["children"], FileNode);
-	        this.isLoaded = source["isLoaded"];
+	        this.followRenames = source["followRenames"];
+	        this.includeGraph = source["includeGraph"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -283,34 +422,1497 @@ export namespace service {
 		    return a;
 		}
 	}
-	export class FileStatus {
-	    file: string;
-	    status: string;
-	    staged: boolean;
+	export class CommitIdentity {
+	    name: string;
+	    email: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileStatus(source);
+	        return new CommitIdentity(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.file = source["file"];
-	        this.status = source["status"];
-	        this.staged = source["staged"];
+	        this.name = source["name"];
+	        this.email = source["email"];
 	    }
 	}
-	export class KeyBinding {
-	    key: string;
-	    modifiers: string[];
+	export class GraphEdge {
+	    from: number;
+	    to: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new KeyBinding(source);
+	        return new GraphEdge(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.key = source["key"];
-	        this.modifiers = source["modifiers"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+	export class GraphRow {
+	    lane: number;
+	    laneCount: number;
+	    incoming: GraphEdge[];
+	    outgoing: GraphEdge[];
+	    isMerge: boolean;
+	    isFork: boolean;
+	    branches: string[];
+	    tags: string[];
+	    isHead: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GraphRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lane = source["lane"];
+	        this.laneCount = source["laneCount"];
+	        this.incoming = this.convertValues(source["incoming"], GraphEdge);
+	        this.outgoing = this.convertValues(source["outgoing"], GraphEdge);
+	        this.isMerge = source["isMerge"];
+	        this.isFork = source["isFork"];
+	        this.branches = source["branches"];
+	        this.tags = source["tags"];
+	        this.isHead = source["isHead"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitInfo {
+	    hash: string;
+	    message: string;
+	    author: string;
+	    authorEmail: string;
+	    // Go type: time
+	    date: any;
+	    parentHashes: string[];
+	    hasMore: boolean;
+	    path?: string;
+	    graph?: GraphRow;
+	    tags?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.message = source["message"];
+	        this.author = source["author"];
+	        this.authorEmail = source["authorEmail"];
+	        this.date = this.convertValues(source["date"], null);
+	        this.parentHashes = source["parentHashes"];
+	        this.hasMore = source["hasMore"];
+	        this.path = source["path"];
+	        this.graph = this.convertValues(source["graph"], GraphRow);
+	        this.tags = source["tags"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitMessageIssue {
+	    rule: string;
+	    message: string;
+	    line: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitMessageIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rule = source["rule"];
+	        this.message = source["message"];
+	        this.line = source["line"];
+	    }
+	}
+	export class CommitOptions {
+	    amend: boolean;
+	    all: boolean;
+	    allowEmpty: boolean;
+	    signOff: boolean;
+	    sign: boolean;
+	    passphrase: string;
+	    author?: CommitIdentity;
+	    committer?: CommitIdentity;
+	    skipHooks: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.amend = source["amend"];
+	        this.all = source["all"];
+	        this.allowEmpty = source["allowEmpty"];
+	        this.signOff = source["signOff"];
+	        this.sign = source["sign"];
+	        this.passphrase = source["passphrase"];
+	        this.author = this.convertValues(source["author"], CommitIdentity);
+	        this.committer = this.convertValues(source["committer"], CommitIdentity);
+	        this.skipHooks = source["skipHooks"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitResult {
+	    hash: string;
+	    issues: CommitMessageIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.issues = this.convertValues(source["issues"], CommitMessageIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitSearchOptions {
+	    branch: string;
+	    allRefs: boolean;
+	    message: string;
+	    content: string;
+	    contentRegex: boolean;
+	    ignoreCase: boolean;
+	    paths: string[];
+	    author: string;
+	    committer: string;
+	    // Go type: time
+	    authorSince: any;
+	    // Go type: time
+	    authorUntil: any;
+	    // Go type: time
+	    committerSince: any;
+	    // Go type: time
+	    committerUntil: any;
+	    merges: string;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitSearchOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.allRefs = source["allRefs"];
+	        this.message = source["message"];
+	        this.content = source["content"];
+	        this.contentRegex = source["contentRegex"];
+	        this.ignoreCase = source["ignoreCase"];
+	        this.paths = source["paths"];
+	        this.author = source["author"];
+	        this.committer = source["committer"];
+	        this.authorSince = this.convertValues(source["authorSince"], null);
+	        this.authorUntil = this.convertValues(source["authorUntil"], null);
+	        this.committerSince = this.convertValues(source["committerSince"], null);
+	        this.committerUntil = this.convertValues(source["committerUntil"], null);
+	        this.merges = source["merges"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CompareOptions {
+	    base: string;
+	    target: string;
+	    mergeBase: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.base = source["base"];
+	        this.target = source["target"];
+	        this.mergeBase = source["mergeBase"];
+	    }
+	}
+	export class CompareResult {
+	    base: string;
+	    target: string;
+	    mergeBase: string;
+	    files: FileDiff[];
+	    stats: DiffStats;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.base = source["base"];
+	        this.target = source["target"];
+	        this.mergeBase = source["mergeBase"];
+	        this.files = this.convertValues(source["files"], FileDiff);
+	        this.stats = this.convertValues(source["stats"], DiffStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConflictRegion {
+	    startLine: number;
+	    endLine: number;
+	    ours: string;
+	    base: string;
+	    theirs: string;
+	    oursLabel: string;
+	    theirsLabel: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConflictRegion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startLine = source["startLine"];
+	        this.endLine = source["endLine"];
+	        this.ours = source["ours"];
+	        this.base = source["base"];
+	        this.theirs = source["theirs"];
+	        this.oursLabel = source["oursLabel"];
+	        this.theirsLabel = source["theirsLabel"];
+	    }
+	}
+	export class ConflictInfo {
+	    path: string;
+	    base: string;
+	    ours: string;
+	    theirs: string;
+	    hasBase: boolean;
+	    hasOurs: boolean;
+	    hasTheirs: boolean;
+	    isBinary: boolean;
+	    working: string;
+	    regions: ConflictRegion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConflictInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.base = source["base"];
+	        this.ours = source["ours"];
+	        this.theirs = source["theirs"];
+	        this.hasBase = source["hasBase"];
+	        this.hasOurs = source["hasOurs"];
+	        this.hasTheirs = source["hasTheirs"];
+	        this.isBinary = source["isBinary"];
+	        this.working = source["working"];
+	        this.regions = this.convertValues(source["regions"], ConflictRegion);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ConflictResolution {
+	    strategy: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConflictResolution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.strategy = source["strategy"];
+	        this.content = source["content"];
+	    }
+	}
+	export class DiffPatchOptions {
+	    files: string[];
+	    staged: boolean;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffPatchOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = source["files"];
+	        this.staged = source["staged"];
+	        this.message = source["message"];
+	    }
+	}
+	
+	export class GitConfig {
+	    backends: {[key: string]: string};
+	    // Go type: struct { Enabled bool "json:\"enabled\" mapstructure:\"enabled\""; KeyFile string "json:\"keyFile\" mapstructure:\"keyFile\""; KeyID string "json:\"keyId\" mapstructure:\"keyId\"" }
+	    signing: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backends = source["backends"];
+	        this.signing = this.convertValues(source["signing"], Object);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EditorConfig {
+	    // Go type: struct { Theme string "json:\"theme\" mapstructure:\"theme\""; FontSize int "json:\"fontSize\" mapstructure:\"fontSize\""; TabSize int "json:\"tabSize\" mapstructure:\"tabSize\""; WordWrap bool "json:\"wordWrap\" mapstructure:\"wordWrap\""; LineNumbers bool "json:\"lineNumbers\" mapstructure:\"lineNumbers\""; RelativeLines bool "json:\"relativeLines\" mapstructure:\"relativeLines\""; Minimap bool "json:\"minimap\" mapstructure:\"minimap\""; StickyScroll bool "json:\"stickyScroll\" mapstructure:\"stickyScroll\""; Vim struct { Enabled bool "json:\"enabled\" mapstructure:\"enabled\""; DefaultMode string "json:\"defaultMode\" mapstructure:\"defaultMode\"" } "json:\"vim\" mapstructure:\"vim\"" }
+	    editor: any;
+	    // Go type: struct { DefaultShell string "json:\"defaultShell\" mapstructure:\"defaultShell\""; FontSize int "json:\"fontSize\" mapstructure:\"fontSize\""; FontFamily string "json:\"fontFamily\" mapstructure:\"fontFamily\""; Theme struct { Background string "json:\"background\" mapstructure:\"background\""; Foreground string "json:\"foreground\" mapstructure:\"foreground\""; Cursor string "json:\"cursor\" mapstructure:\"cursor\""; SelectionBackground string "json:\"selectionBackground\" mapstructure:\"selectionBackground\""; SelectionForeground string "json:\"selectionForeground\" mapstructure:\"selectionForeground\"" } "json:\"theme\" mapstructure:\"theme\"" }
+	    terminal: any;
+	    keyboard: struct { CustomBindings map[string]service.;
+	    git: GitConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditorConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.editor = this.convertValues(source["editor"], Object);
+	        this.terminal = this.convertValues(source["terminal"], Object);
+	        this.keyboard = this.convertValues(source["keyboard"], Object);
+	        this.git = this.convertValues(source["git"], GitConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileAttributes {
+	    path: string;
+	    binary: boolean;
+	    textDiff: boolean;
+	    text: string;
+	    eol: string;
+	    diffDriver: string;
+	    generated: boolean;
+	    lfs: boolean;
+	    encoding: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileAttributes(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.binary = source["binary"];
+	        this.textDiff = source["textDiff"];
+	        this.text = source["text"];
+	        this.eol = source["eol"];
+	        this.diffDriver = source["diffDriver"];
+	        this.generated = source["generated"];
+	        this.lfs = source["lfs"];
+	        this.encoding = source["encoding"];
+	    }
+	}
+	
+	export class FileNode {
+	    name: string;
+	    path: string;
+	    type: string;
+	    size?: number;
+	    // Go type: time
+	    lastModified: any;
+	    children?: FileNode[];
+	    isLoaded: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.type = source["type"];
+	        this.size = source["size"];
+	        this.lastModified = this.convertValues(source["lastModified"], null);
+	        this.children = this.convertValues(source["children"], FileNode);
+	        this.isLoaded = source["isLoaded"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileStatus {
+	    file: string;
+	    status: string;
+	    staged: boolean;
+	    origPath?: string;
+	    submodule?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.status = source["status"];
+	        this.staged = source["staged"];
+	        this.origPath = source["origPath"];
+	        this.submodule = source["submodule"];
+	    }
+	}
+	
+	
+	
+	export class IgnoreCheck {
+	    path: string;
+	    ignored: boolean;
+	    tracked: boolean;
+	    source: string;
+	    line: number;
+	    pattern: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IgnoreCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.ignored = source["ignored"];
+	        this.tracked = source["tracked"];
+	        this.source = source["source"];
+	        this.line = source["line"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class KeyBinding {
+	    key: string;
+	    modifiers: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new KeyBinding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.modifiers = source["modifiers"];
+	    }
+	}
+	
+	
+	export class MergeOptions {
+	    noFastForward: boolean;
+	    fastForwardOnly: boolean;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.noFastForward = source["noFastForward"];
+	        this.fastForwardOnly = source["fastForwardOnly"];
+	        this.message = source["message"];
+	    }
+	}
+	export class MergeResult {
+	    status: string;
+	    commit: string;
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.commit = source["commit"];
+	        this.conflicts = source["conflicts"];
+	    }
+	}
+	export class MergeState {
+	    inProgress: boolean;
+	    mergeHead: string;
+	    message: string;
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inProgress = source["inProgress"];
+	        this.mergeHead = source["mergeHead"];
+	        this.message = source["message"];
+	        this.conflicts = source["conflicts"];
+	    }
+	}
+	export class PatchApplyOptions {
+	    path: string;
+	    patch: string;
+	    staged: boolean;
+	    worktree: boolean;
+	    threeWay: boolean;
+	    check: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PatchApplyOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.patch = source["patch"];
+	        this.staged = source["staged"];
+	        this.worktree = source["worktree"];
+	        this.threeWay = source["threeWay"];
+	        this.check = source["check"];
+	    }
+	}
+	export class PatchHunk {
+	    header: string;
+	    oldStart: number;
+	    oldLines: number;
+	    newStart: number;
+	    newLines: number;
+	    applies: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PatchHunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.header = source["header"];
+	        this.oldStart = source["oldStart"];
+	        this.oldLines = source["oldLines"];
+	        this.newStart = source["newStart"];
+	        this.newLines = source["newLines"];
+	        this.applies = source["applies"];
+	    }
+	}
+	export class PatchFileResult {
+	    path: string;
+	    oldPath: string;
+	    added: number;
+	    deleted: number;
+	    binary: boolean;
+	    applies: boolean;
+	    conflicted: boolean;
+	    error: string;
+	    hunks: PatchHunk[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PatchFileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.oldPath = source["oldPath"];
+	        this.added = source["added"];
+	        this.deleted = source["deleted"];
+	        this.binary = source["binary"];
+	        this.applies = source["applies"];
+	        this.conflicted = source["conflicted"];
+	        this.error = source["error"];
+	        this.hunks = this.convertValues(source["hunks"], PatchHunk);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PatchApplyResult {
+	    applied: boolean;
+	    error: string;
+	    files: PatchFileResult[];
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PatchApplyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.applied = source["applied"];
+	        this.error = source["error"];
+	        this.files = this.convertValues(source["files"], PatchFileResult);
+	        this.conflicts = source["conflicts"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PatchExportOptions {
+	    commits: string[];
+	    output: string;
+	    single: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PatchExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.commits = source["commits"];
+	        this.output = source["output"];
+	        this.single = source["single"];
+	    }
+	}
+	
+	
+	export class PickOptions {
+	    noCommit: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PickOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.noCommit = source["noCommit"];
+	    }
+	}
+	export class PickState {
+	    inProgress: boolean;
+	    operation: string;
+	    current: string;
+	    remaining: string[];
+	    head: string;
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PickState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inProgress = source["inProgress"];
+	        this.operation = source["operation"];
+	        this.current = source["current"];
+	        this.remaining = source["remaining"];
+	        this.head = source["head"];
+	        this.conflicts = source["conflicts"];
+	    }
+	}
+	export class RebaseStep {
+	    action: string;
+	    hash: string;
+	    message: string;
+	    newMessage: string;
+	    author: string;
+	    // Go type: time
+	    date: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new RebaseStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.hash = source["hash"];
+	        this.message = source["message"];
+	        this.newMessage = source["newMessage"];
+	        this.author = source["author"];
+	        this.date = this.convertValues(source["date"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RebasePlan {
+	    base: string;
+	    steps: RebaseStep[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RebasePlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.base = source["base"];
+	        this.steps = this.convertValues(source["steps"], RebaseStep);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RebaseState {
+	    inProgress: boolean;
+	    branch: string;
+	    onto: string;
+	    origHead: string;
+	    head: string;
+	    done: RebaseStep[];
+	    current?: RebaseStep;
+	    remaining: RebaseStep[];
+	    stopReason: string;
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RebaseState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inProgress = source["inProgress"];
+	        this.branch = source["branch"];
+	        this.onto = source["onto"];
+	        this.origHead = source["origHead"];
+	        this.head = source["head"];
+	        this.done = this.convertValues(source["done"], RebaseStep);
+	        this.current = this.convertValues(source["current"], RebaseStep);
+	        this.remaining = this.convertValues(source["remaining"], RebaseStep);
+	        this.stopReason = source["stopReason"];
+	        this.conflicts = source["conflicts"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ReflogEntry {
+	    selector: string;
+	    oldHash: string;
+	    newHash: string;
+	    committer: string;
+	    email: string;
+	    // Go type: time
+	    date: any;
+	    action: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReflogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.selector = source["selector"];
+	        this.oldHash = source["oldHash"];
+	        this.newHash = source["newHash"];
+	        this.committer = source["committer"];
+	        this.email = source["email"];
+	        this.date = this.convertValues(source["date"], null);
+	        this.action = source["action"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RemoteInfo {
+	    name: string;
+	    fetchUrls: string[];
+	    pushUrls: string[];
+	    fetch: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RemoteInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.fetchUrls = source["fetchUrls"];
+	        this.pushUrls = source["pushUrls"];
+	        this.fetch = source["fetch"];
+	    }
+	}
+	export class SubmoduleState {
+	    commitChanged: boolean;
+	    modified: boolean;
+	    untracked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubmoduleState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.commitChanged = source["commitChanged"];
+	        this.modified = source["modified"];
+	        this.untracked = source["untracked"];
+	    }
+	}
+	export class StatusEntry {
+	    path: string;
+	    origPath?: string;
+	    index: string;
+	    worktree: string;
+	    unmerged: boolean;
+	    score?: number;
+	    headMode?: string;
+	    indexMode?: string;
+	    worktreeMode?: string;
+	    headHash?: string;
+	    indexHash?: string;
+	    submodule?: SubmoduleState;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatusEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.origPath = source["origPath"];
+	        this.index = source["index"];
+	        this.worktree = source["worktree"];
+	        this.unmerged = source["unmerged"];
+	        this.score = source["score"];
+	        this.headMode = source["headMode"];
+	        this.indexMode = source["indexMode"];
+	        this.worktreeMode = source["worktreeMode"];
+	        this.headHash = source["headHash"];
+	        this.indexHash = source["indexHash"];
+	        this.submodule = this.convertValues(source["submodule"], SubmoduleState);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ResetPreview {
+	    target: string;
+	    mode: string;
+	    branch: string;
+	    removedCommits: CommitInfo[];
+	    lostChanges: StatusEntry[];
+	    unstagedFiles: StatusEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ResetPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.mode = source["mode"];
+	        this.branch = source["branch"];
+	        this.removedCommits = this.convertValues(source["removedCommits"], CommitInfo);
+	        this.lostChanges = this.convertValues(source["lostChanges"], StatusEntry);
+	        this.unstagedFiles = this.convertValues(source["unstagedFiles"], StatusEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RestoreOptions {
+	    source: string;
+	    staged: boolean;
+	    worktree: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RestoreOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.staged = source["staged"];
+	        this.worktree = source["worktree"];
+	    }
+	}
+	export class StashEntry {
+	    index: number;
+	    ref: string;
+	    hash: string;
+	    message: string;
+	    branch: string;
+	    // Go type: time
+	    date: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new StashEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.ref = source["ref"];
+	        this.hash = source["hash"];
+	        this.message = source["message"];
+	        this.branch = source["branch"];
+	        this.date = this.convertValues(source["date"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StashOptions {
+	    message: string;
+	    includeUntracked: boolean;
+	    keepIndex: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StashOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message = source["message"];
+	        this.includeUntracked = source["includeUntracked"];
+	        this.keepIndex = source["keepIndex"];
+	    }
+	}
+	
+	export class StatusOptions {
+	    ignored: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatusOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ignored = source["ignored"];
+	    }
+	}
+	export class SubmoduleInfo {
+	    name: string;
+	    path: string;
+	    url: string;
+	    branch: string;
+	    recordedCommit: string;
+	    currentCommit: string;
+	    initialized: boolean;
+	    outOfSync: boolean;
+	    dirty: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubmoduleInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.url = source["url"];
+	        this.branch = source["branch"];
+	        this.recordedCommit = source["recordedCommit"];
+	        this.currentCommit = source["currentCommit"];
+	        this.initialized = source["initialized"];
+	        this.outOfSync = source["outOfSync"];
+	        this.dirty = source["dirty"];
+	    }
+	}
+	
+	export class SubmoduleUpdateOptions {
+	    paths: string[];
+	    init: boolean;
+	    recursive: boolean;
+	    remote: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubmoduleUpdateOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.paths = source["paths"];
+	        this.init = source["init"];
+	        this.recursive = source["recursive"];
+	        this.remote = source["remote"];
+	    }
+	}
+	export class TagInfo {
+	    name: string;
+	    hash: string;
+	    annotated: boolean;
+	    object: string;
+	    tagger: string;
+	    taggerEmail: string;
+	    // Go type: time
+	    date: any;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.hash = source["hash"];
+	        this.annotated = source["annotated"];
+	        this.object = source["object"];
+	        this.tagger = source["tagger"];
+	        this.taggerEmail = source["taggerEmail"];
+	        this.date = this.convertValues(source["date"], null);
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TagOptions {
+	    name: string;
+	    target: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.target = source["target"];
+	        this.message = source["message"];
+	    }
+	}
+	export class UndoEntry {
+	    id: number;
+	    operation: string;
+	    // Go type: time
+	    date: any;
+	    branch: string;
+	    hash: string;
+	    afterBranch: string;
+	    afterHash: string;
+	    restore: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UndoEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.operation = source["operation"];
+	        this.date = this.convertValues(source["date"], null);
+	        this.branch = source["branch"];
+	        this.hash = source["hash"];
+	        this.afterBranch = source["afterBranch"];
+	        this.afterHash = source["afterHash"];
+	        this.restore = source["restore"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UndoPreview {
+	    entry: UndoEntry;
+	    description: string;
+	    currentBranch: string;
+	    currentHash: string;
+	    moved: boolean;
+	    removedCommits: CommitInfo[];
+	    restoredCommits: CommitInfo[];
+	    files: FileDiff[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UndoPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = this.convertValues(source["entry"], UndoEntry);
+	        this.description = source["description"];
+	        this.currentBranch = source["currentBranch"];
+	        this.currentHash = source["currentHash"];
+	        this.moved = source["moved"];
+	        this.removedCommits = this.convertValues(source["removedCommits"], CommitInfo);
+	        this.restoredCommits = this.convertValues(source["restoredCommits"], CommitInfo);
+	        this.files = this.convertValues(source["files"], FileDiff);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorktreeInfo {
+	    path: string;
+	    head: string;
+	    branch: string;
+	    isMain: boolean;
+	    isCurrent: boolean;
+	    bare: boolean;
+	    detached: boolean;
+	    locked: boolean;
+	    lockReason: string;
+	    prunable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.head = source["head"];
+	        this.branch = source["branch"];
+	        this.isMain = source["isMain"];
+	        this.isCurrent = source["isCurrent"];
+	        this.bare = source["bare"];
+	        this.detached = source["detached"];
+	        this.locked = source["locked"];
+	        this.lockReason = source["lockReason"];
+	        this.prunable = source["prunable"];
+	    }
+	}
+	export class WorktreeOptions {
+	    path: string;
+	    branch: string;
+	    newBranch: boolean;
+	    startPoint: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.branch = source["branch"];
+	        this.newBranch = source["newBranch"];
+	        this.startPoint = source["startPoint"];
 	    }
 	}
 
@@ -17,6 +17,7 @@ import {
     GetHeadCommit,
     GetFileDiff
 } from '@/lib/wailsjs/go/main/App';
+import { EventsOn } from '@/lib/wailsjs/runtime/runtime';
 import { fileStore } from '@/stores/fileStore';
 import type { service } from '@/lib/wailsjs/go/models';
 
@@ -59,6 +60,14 @@ function createGitStore() {
         initialized: false
     });
 
+    // The backend watches open repositories and pushes their status when it changes
+    EventsOn('git:status-changed', (event: { projectPath: string; files: service.FileStatus[] }) => {
+        if (event.projectPath !== get(fileStore).currentProjectPath) {
+            return;
+        }
+        update(state => ({ ...state, gitStatus: event.files }));
+    });
+
     return {
         subscribe,
 
@@ -391,14 +400,14 @@ function createGitStore() {
             }
         },
 
-        async commit(message: string): Promise<void> {
+        async commit(message: string, options: Partial<service.CommitOptions> = {}): Promise<void> {
             const projectPath = get(fileStore).currentProjectPath;
             if (!projectPath) {
                 return;
             }
 
             try {
-                await Commit(projectPath, message);
+                await Commit(projectPath, message, options as service.CommitOptions);
                 await this.getCommits(); // This will also update HEAD
                 await this.refreshStatus();
             } catch (error) {
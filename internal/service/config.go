@@ -38,6 +38,17 @@ type EditorConfig struct {
 	Keyboard struct {
 		CustomBindings map[string]KeyBinding `json:"customBindings" mapstructure:"customBindings"`
 	} `json:"keyboard" mapstructure:"keyboard"`
+	Git GitConfig `json:"git" mapstructure:"git"`
+}
+
+// GitConfig represents the git integration configuration
+type GitConfig struct {
+	Backends map[string]string `json:"backends" mapstructure:"backends"` // Backend used per operation
+	Signing  struct {
+		Enabled bool   `json:"enabled" mapstructure:"enabled"` // Sign every commit
+		KeyFile string `json:"keyFile" mapstructure:"keyFile"` // OpenPGP private key file, armored or binary
+		KeyID   string `json:"keyId" mapstructure:"keyId"`     // Key to use when the file holds several, empty for the first one
+	} `json:"signing" mapstructure:"signing"`
 }
 
 // KeyBinding represents a keyboard shortcut configuration
@@ -130,7 +141,19 @@ terminal:
     selectionForeground: "#d1d5db"
 
 keyboard:
-  customBindings: {}`
+  customBindings: {}
+
+git:
+  # Backend used per operation: "go-git" or "cli" (system git binary)
+  backends:
+    status: go-git
+    rebase: cli
+    cherryPick: cli
+    revert: cli
+  signing:
+    enabled: false
+    keyFile: ""  # e.g. exported with: gpg --export-secret-keys --armor <id>
+    keyId: ""`
 
 	return os.WriteFile(path, []byte(defaultConfig), 0644)
 }
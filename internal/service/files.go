@@ -10,7 +10,6 @@ import (
 	"sync"
 	"time"
 
-	ignore "github.com/sabhiram/go-gitignore"
 	"github.com/sahilm/fuzzy"
 	"io/fs"
 )
@@ -32,14 +31,12 @@ type FileService struct {
 	cache     map[string]*FileNode
 	cacheLock sync.RWMutex
 	// TODO: Add file watcher
-	ignores map[string]*ignore.GitIgnore
 }
 
 // NewFileService creates a new file service instance
 func NewFileService() *FileService {
 	return &FileService{
-		cache:   make(map[string]*FileNode),
-		ignores: make(map[string]*ignore.GitIgnore),
+		cache: make(map[string]*FileNode),
 	}
 }
 
@@ -274,39 +271,19 @@ func (s *FileService) invalidateNode(node *FileNode, targetPath string) bool {
 	return false
 }
 
-// loadGitIgnore loads the gitignore file for a directory if it exists
-func (s *FileService) loadGitIgnore(dirPath string) *ignore.GitIgnore {
-	if ig, ok := s.ignores[dirPath]; ok {
-		return ig
-	}
-
-	gitignorePath := filepath.Join(dirPath, ".gitignore")
-	if ig, err := ignore.CompileIgnoreFile(gitignorePath); err == nil {
-		s.ignores[dirPath] = ig
-		return ig
-	}
-	return nil
-}
-
-// isIgnored checks if a path should be ignored based on gitignore rules
-func (s *FileService) isIgnored(rootPath, path string) bool {
+// isIgnored checks if a path should be ignored, with the rules git uses for its status
+func (s *FileService) isIgnored(matcher *ignoreMatcher, path string, isDir bool) bool {
 	// Always ignore .git directory
 	if strings.Contains(path, "/.git/") || strings.HasSuffix(path, "/.git") {
 		return true
 	}
 
-	// Check each parent directory for .gitignore rules
-	dir := filepath.Dir(path)
-	for dir != "/" && strings.HasPrefix(path, rootPath) {
-		if ig := s.loadGitIgnore(dir); ig != nil {
-			relPath, err := filepath.Rel(dir, path)
-			if err == nil && ig.MatchesPath(relPath) {
-				return true
-			}
-		}
-		dir = filepath.Dir(dir)
+	rel, err := filepath.Rel(matcher.root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
 	}
-	return false
+	_, excluded, err := matcher.match(filepath.ToSlash(rel), isDir)
+	return err == nil && excluded
 }
 
 // SearchFiles performs a fuzzy search on files in a directory
@@ -314,8 +291,14 @@ func (s *FileService) SearchFiles(ctx context.Context, dirPath, query string) ([
 	var allFiles []*FileNode
 	var searchPaths []string
 
+	// Ignore files are read again for every search, so edits to them apply right away
+	matcher, err := newIgnoreMatcher(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Walk the directory tree
-	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -328,7 +311,7 @@ func (s *FileService) SearchFiles(ctx context.Context, dirPath, query string) ([
 		}
 
 		// Skip if ignored
-		if s.isIgnored(dirPath, path) {
+		if s.isIgnored(matcher, path, d.IsDir()) {
 			if d.IsDir() {
 				return filepath.SkipDir
 			}
@@ -1,17 +1,19 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/utils/diff"
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -19,9 +21,11 @@ import (
 
 // FileStatus represents the status of a file in the Git repository
 type FileStatus struct {
-	File   string `json:"file"`   // File path relative to repository root
-	Status string `json:"status"` // Status code: "M" for modified, "A" for added, "D" for deleted and "?" for untracked
-	Staged bool   `json:"staged"` // Whether the file is staged
+	File      string `json:"file"`                // File path relative to repository root
+	Status    string `json:"status"`              // Status code: "M" for modified, "T" for type changed, "A" for added, "D" for deleted, "R" for renamed, "C" for copied, "?" for untracked, "!" for ignored and "U" for conflicted
+	Staged    bool   `json:"staged"`              // Whether the file is staged
+	OrigPath  string `json:"origPath,omitempty"`  // Original path of a staged rename or copy
+	Submodule bool   `json:"submodule,omitempty"` // Whether the path is a submodule, whose checked-out commit changed
 }
 
 // BranchInfo represents information about a Git branch
@@ -29,6 +33,7 @@ type BranchInfo struct {
 	Name     string `json:"name"`
 	IsRemote bool   `json:"isRemote"`
 	IsHead   bool   `json:"isHead"`
+	Upstream string `json:"upstream,omitempty"` // Branch tracked by a local branch, like "origin/main"
 }
 
 // CommitInfo represents information about a Git commit
@@ -39,28 +44,38 @@ type CommitInfo struct {
 	AuthorEmail  string    `json:"authorEmail"`
 	Date         time.Time `json:"date"`
 	ParentHashes []string  `json:"parentHashes"`
-	HasMore      bool      `json:"hasMore"` // Indicates if there are more commits after this one
+	HasMore      bool      `json:"hasMore"`         // Indicates if there are more commits after this one
+	Path         string    `json:"path,omitempty"`  // Path of the filtered file in this commit, which differs from the filter after a rename
+	Graph        *GraphRow `json:"graph,omitempty"` // Commit graph layout for this row, when requested
+	Tags         []string  `json:"tags,omitempty"`  // Tags pointing at this commit
 }
 
 // CommitFilter contains options for filtering commits
 type CommitFilter struct {
-	Branch      string    `json:"branch"`      // Branch to list commits from
-	StartHash   string    `json:"startHash"`   // Start listing from this commit
-	Limit       int       `json:"limit"`       // Max number of commits to return
-	Offset      int       `json:"offset"`      // Skip this many commits (numeric offset)
-	OffsetHash  string    `json:"offsetHash"`  // Start listing after this commit hash (more efficient for pagination)
-	Author      string    `json:"author"`      // Filter by author
-	SearchQuery string    `json:"searchQuery"` // Search in commit messages
-	StartDate   time.Time `json:"startDate"`   // Filter commits after this date
-	EndDate     time.Time `json:"endDate"`     // Filter commits before this date
+	Branch        string    `json:"branch"`        // Branch to list commits from
+	StartHash     string    `json:"startHash"`     // Start listing from this commit
+	Limit         int       `json:"limit"`         // Max number of commits to return
+	Offset        int       `json:"offset"`        // Skip this many commits (numeric offset)
+	OffsetHash    string    `json:"offsetHash"`    // Start listing after this commit hash (more efficient for pagination)
+	Author        string    `json:"author"`        // Filter by author
+	SearchQuery   string    `json:"searchQuery"`   // Search in commit messages
+	StartDate     time.Time `json:"startDate"`     // Filter commits after this date
+	EndDate       time.Time `json:"endDate"`       // Filter commits before this date
+	Path          string    `json:"path"`          // Only list commits touching this file or directory
+	FollowRenames bool      `json:"followRenames"` // Keep following a file across renames when filtering by path
+	IncludeGraph  bool      `json:"includeGraph"`  // Compute the commit graph layout for each returned commit
 }
 
 // FileDiff represents the diff information for a file
 type FileDiff struct {
-	Path     string    `json:"path"`     // File path
-	Content  string    `json:"content"`  // Diff content in unified format
-	Stats    DiffStats `json:"stats"`    // Statistics about the changes
-	IsBinary bool      `json:"isBinary"` // Whether the file is binary
+	Path      string    `json:"path"`                // File path
+	Content   string    `json:"content"`             // Diff content in unified format
+	Stats     DiffStats `json:"stats"`               // Statistics about the changes
+	IsBinary  bool      `json:"isBinary"`            // Whether the file is binary
+	Status    string    `json:"status,omitempty"`    // Change status for tree diffs: "A" added, "M" modified, "D" deleted, "R" renamed
+	OldPath   string    `json:"oldPath,omitempty"`   // Previous path of a renamed file
+	Generated bool      `json:"generated,omitempty"` // Marked linguist-generated, can be shown collapsed
+	LFS       *LFSDiff  `json:"lfs,omitempty"`       // Set for files stored with Git LFS, which have no text diff
 }
 
 // DiffStats contains statistics about changes in a diff
@@ -72,12 +87,56 @@ type DiffStats struct {
 
 // GitService handles Git operations for projects
 type GitService struct {
-	// We might want to add a cache of repositories later
+	// Open repositories per project
+	repos *repoCache
+
+	// Blame results per project, commit and file
+	blameCache map[string]*BlameResult
+	blameLock  sync.Mutex
+
+	// Backend used for each operation
+	backends map[string]gitBackend
+
+	// Operations that can be undone per project
+	undo     map[string][]UndoEntry
+	undoSeq  int
+	undoLock sync.Mutex
+
+	// Cancel functions of running clones by identifier
+	clones     map[string]context.CancelFunc
+	clonesLock sync.Mutex
+
+	// Cancel functions of running commit searches by identifier
+	searches     map[string]context.CancelFunc
+	searchesLock sync.Mutex
+
+	config GitConfig
+
+	// Receives events, such as hook output, to forward to the frontend
+	onEvent func(event string, data interface{})
+}
+
+// NewGitService creates a new Git service instance.
+// Operations not listed in the configured backends use the default backend.
+func NewGitService(config GitConfig, onEvent func(event string, data interface{})) *GitService {
+	repos := newRepoCache()
+	return &GitService{
+		repos:      repos,
+		blameCache: make(map[string]*BlameResult),
+		backends:   newBackends(config.Backends, repos),
+		undo:       make(map[string][]UndoEntry),
+		clones:     make(map[string]context.CancelFunc),
+		searches:   make(map[string]context.CancelFunc),
+		config:     config,
+		onEvent:    onEvent,
+	}
 }
 
-// NewGitService creates a new Git service instance
-func NewGitService() *GitService {
-	return &GitService{}
+// emit sends an event to the event handler, if there is one
+func (s *GitService) emit(event string, data interface{}) {
+	if s.onEvent != nil {
+		s.onEvent(event, data)
+	}
 }
 
 // IsGitRepository checks if the given directory is a Git repository
@@ -91,7 +150,7 @@ func (s *GitService) IsGitRepository(projectPath string) (bool, error) {
 	}
 
 	// Try to open the repository
-	_, err = git.PlainOpen(absPath)
+	_, err = s.repository(absPath)
 	if err != nil {
 		if errors.Is(err, git.ErrRepositoryNotExists) {
 			// Not a Git repository, but not an error
@@ -139,67 +198,23 @@ func (s *GitService) GetStatus(projectPath string) ([]FileStatus, error) {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	// Open the repository
-	repo, err := git.PlainOpen(absPath)
+	entries, err := s.status(absPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open repository: %w", err)
-	}
-
-	// Get the working tree
-	worktree, err := repo.Worktree()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get worktree: %w", err)
-	}
-
-	// Get the status
-	status, err := worktree.Status()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get status: %w", err)
-	}
-
-	// Convert status to our format
-	var files []FileStatus
-	for file, fileStatus := range status {
-		// Skip unmodified files
-		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
-			continue
-		}
-
-		// For untracked files
-		if fileStatus.Worktree == git.Untracked {
-			files = append(files, FileStatus{
-				File:   file,
-				Staged: false,
-				Status: string(git.Untracked),
-			})
-			continue
-		}
-
-		// Handle staged changes
-		if fileStatus.Staging != git.Unmodified {
-			files = append(files, FileStatus{
-				File:   file,
-				Staged: true,
-				Status: string(fileStatus.Staging),
-			})
-		}
-
-		// Handle unstaged changes
-		if fileStatus.Worktree != git.Unmodified {
-			files = append(files, FileStatus{
-				File:   file,
-				Staged: false,
-				Status: string(fileStatus.Worktree),
-			})
-		}
+		return nil, err
 	}
+	return fileStatuses(entries), nil
+}
 
-	return files, nil
+// openRepository opens the repository at the given path. Linked worktrees created
+// with "git worktree add" share objects and refs with their main repository
+// through the commondir file, which go-git only follows when asked to.
+func openRepository(projectPath string) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(projectPath, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
 }
 
 // getWorktree is a helper function that returns the worktree for a given project path
 func (s *GitService) getWorktree(projectPath string) (*git.Worktree, error) {
-	repo, err := git.PlainOpen(projectPath)
+	repo, err := s.repository(projectPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -241,28 +256,25 @@ func (s *GitService) UnstageFile(projectPath string, file string) error {
 	return nil
 }
 
-// DiscardChanges discards changes in an unstaged file, reverting it to the last commit
+// DiscardChanges discards the unstaged changes of a file, reverting it to its staged
+// version, or to the last commit when it has none. Untracked files are deleted.
 func (s *GitService) DiscardChanges(projectPath string, file string) error {
 	// Open the repository
-	repo, err := git.PlainOpen(projectPath)
+	repo, err := s.repository(projectPath)
 	if err != nil {
 		return fmt.Errorf("failed to open repository: %w", err)
 	}
 
-	// Get worktree to check file status
-	worktree, err := repo.Worktree()
-	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
-	}
+	// The file is rewritten without touching the index, which the cached status would not notice
+	defer s.invalidateStatus(projectPath)
 
 	// Check if file is untracked
-	status, err := worktree.Status()
+	status, err := s.fileStatus(projectPath, file)
 	if err != nil {
-		return fmt.Errorf("failed to get status: %w", err)
+		return err
 	}
 
-	fileStatus := status.File(file)
-	if fileStatus.Staging == git.Untracked {
+	if status != nil && status.Worktree == StateUntracked {
 		fullPath := filepath.Join(projectPath, file)
 		if err := os.Remove(fullPath); err != nil {
 			return fmt.Errorf("failed to delete untracked file: %w", err)
@@ -270,79 +282,58 @@ func (s *GitService) DiscardChanges(projectPath string, file string) error {
 		return nil
 	}
 
-	// Get HEAD commit
-	ref, err := repo.Head()
-	if err != nil {
-		return fmt.Errorf("failed to get HEAD: %w", err)
-	}
-
-	commit, err := repo.CommitObject(ref.Hash())
-	if err != nil {
-		return fmt.Errorf("failed to get commit: %w", err)
-	}
-
-	// Get the tree for the commit
-	tree, err := commit.Tree()
+	// Find the staged version, which new files only have in the index
+	idx, err := repo.Storer.Index()
 	if err != nil {
-		return fmt.Errorf("failed to get tree: %w", err)
+		return fmt.Errorf("failed to get index: %w", err)
 	}
-
-	// Find the file entry in the tree to get both content and mode
-	entry, err := tree.FindEntry(file)
-	if err != nil {
-		return fmt.Errorf("failed to find file in tree: %w", err)
+	var hash plumbing.Hash
+	var mode filemode.FileMode
+	for _, entry := range idx.Entries {
+		if entry.Name == file && entry.Stage == 0 {
+			hash, mode = entry.Hash, entry.Mode
+			break
+		}
 	}
 
-	// Get file object
-	treeFile, err := tree.File(file)
-	if err != nil {
-		return fmt.Errorf("failed to get file from tree: %w", err)
-	}
+	if hash.IsZero() {
+		// Get HEAD commit
+		ref, err := repo.Head()
+		if err != nil {
+			return fmt.Errorf("failed to get HEAD: %w", err)
+		}
 
-	// Get the contents
-	contents, err := treeFile.Contents()
-	if err != nil {
-		return fmt.Errorf("failed to get file contents: %w", err)
-	}
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to get commit: %w", err)
+		}
 
-	// Write the contents back to the file
-	fullPath := filepath.Join(projectPath, file)
-	err = os.WriteFile(fullPath, []byte(contents), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
+		// Get the tree for the commit
+		tree, err := commit.Tree()
+		if err != nil {
+			return fmt.Errorf("failed to get tree: %w", err)
+		}
 
-	// Set the original permissions from git tree entry
-	mode, modeErr := entry.Mode.ToOSFileMode()
-	if modeErr != nil {
-		return fmt.Errorf("failed to convert file mode: %w", modeErr)
-	}
-	if err := os.Chmod(fullPath, mode); err != nil {
-		return fmt.Errorf("failed to set file permissions: %w", err)
+		// Find the file entry in the tree to get both content and mode
+		entry, err := tree.FindEntry(file)
+		if err != nil {
+			return fmt.Errorf("failed to find file in tree: %w", err)
+		}
+		hash, mode = entry.Hash, entry.Mode
 	}
 
-	return nil
-}
-
-// Commit creates a new commit with the staged changes
-func (s *GitService) Commit(projectPath string, message string) error {
-	worktree, err := s.getWorktree(projectPath)
+	contents, err := readBlob(repo, hash)
 	if err != nil {
 		return err
 	}
 
-	// Create the commit
-	_, err = worktree.Commit(message, &git.CommitOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create commit: %w", err)
-	}
-
-	return nil
+	// Write the contents back with the mode git has for the file
+	return writeWorktreeFile(filepath.Join(projectPath, file), contents, mode)
 }
 
 // ListBranches returns a list of all branches in the repository
 func (s *GitService) ListBranches(projectPath string) ([]BranchInfo, error) {
-	repo, err := git.PlainOpen(projectPath)
+	repo, err := s.repository(projectPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -362,13 +353,22 @@ func (s *GitService) ListBranches(projectPath string) ([]BranchInfo, error) {
 		return nil, fmt.Errorf("failed to list branches: %w", err)
 	}
 
+	upstreams, err := branchUpstreams(repo)
+	if err != nil {
+		return nil, err
+	}
+
 	err = branchIter.ForEach(func(ref *plumbing.Reference) error {
 		branchName := ref.Name().Short()
-		branches = append(branches, BranchInfo{
+		branch := BranchInfo{
 			Name:     branchName,
 			IsRemote: false,
 			IsHead:   branchName == currentBranchName,
-		})
+		}
+		if upstream := upstreams[branchName]; upstream != nil {
+			branch.Upstream = upstream.Name
+		}
+		branches = append(branches, branch)
 		return nil
 	})
 	if err != nil {
@@ -404,7 +404,7 @@ func (s *GitService) ListBranches(projectPath string) ([]BranchInfo, error) {
 
 // GetCurrentBranch returns the name of the current branch
 func (s *GitService) GetCurrentBranch(projectPath string) (string, error) {
-	repo, err := git.PlainOpen(projectPath)
+	repo, err := s.repository(projectPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -425,8 +425,13 @@ func (s *GitService) ListCommits(projectPath string, filter CommitFilter) ([]Com
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
+	// Rows are laid out from the parents of each commit, which hidden commits would leave dangling
+	if filter.IncludeGraph && (filter.Path != "" || filter.Author != "" || filter.SearchQuery != "" || !filter.StartDate.IsZero() || !filter.EndDate.IsZero()) {
+		return nil, errors.New("the commit graph cannot be combined with path, author, message or date filters")
+	}
+
 	// Open the repository
-	repo, err := git.PlainOpen(absPath)
+	repo, err := s.repository(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -455,25 +460,69 @@ func (s *GitService) ListCommits(projectPath string, filter CommitFilter) ([]Com
 		startRef = ref.Hash()
 	}
 
-	// Create log options
-	logOptions := &git.LogOptions{
-		From:  startRef,
-		Order: git.LogOrderCommitterTime,
+	// Get the commit iterator. The graph needs children before their parents,
+	// which committer times do not guarantee when clocks were skewed.
+	var commitIter object.CommitIter
+	if filter.IncludeGraph {
+		commitIter, err = topoOrderLog(repo, startRef)
+	} else {
+		commitIter, err = repo.Log(&git.LogOptions{
+			From:  startRef,
+			Order: git.LogOrderCommitterTime,
+		})
 	}
-
-	// Get the commit iterator
-	commitIter, err := repo.Log(logOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit iterator: %w", err)
 	}
 	defer commitIter.Close()
 
+	tags, err := s.tagsByCommit(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
 	var commits []CommitInfo
 	var skipped int
 	var foundOffsetHash bool = filter.OffsetHash == "" // If no offset hash specified, we start collecting immediately
 	var hasMoreCommits bool = false
 
+	var tracker *pathTracker
+	if filter.Path != "" {
+		tracker = &pathTracker{
+			path:   strings.Trim(filepath.ToSlash(filter.Path), "/"),
+			follow: filter.FollowRenames,
+		}
+	}
+
+	// The graph is laid out over the full history walk, so commits skipped
+	// by pagination still take part and rows stay consistent across pages
+	var graph *graphBuilder
+	if filter.IncludeGraph {
+		graph, err = newGraphBuilder(repo, tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = commitIter.ForEach(func(c *object.Commit) error {
+		var graphRow *GraphRow
+		if graph != nil {
+			graphRow = graph.next(c)
+		}
+
+		// Apply path filter first, so renames are followed even for skipped commits
+		var commitPath string
+		if tracker != nil {
+			commitPath = tracker.path
+			touched, err := tracker.touches(c)
+			if err != nil {
+				return err
+			}
+			if !touched {
+				return nil
+			}
+		}
+
 		// Handle hash-based offset
 		if !foundOffsetHash {
 			if c.Hash.String() == filter.OffsetHash {
@@ -528,6 +577,9 @@ func (s *GitService) ListCommits(projectPath string, filter CommitFilter) ([]Com
 			Date:         c.Author.When,
 			ParentHashes: parentHashes,
 			HasMore:      true, // Will be updated after the loop
+			Path:         commitPath,
+			Graph:        graphRow,
+			Tags:         tags[c.Hash],
 		})
 
 		// Check if we've reached the limit
@@ -588,7 +640,7 @@ func (s *GitService) SearchCommits(projectPath string, query string, limit int)
 
 // GetHeadCommit returns the current HEAD commit
 func (s *GitService) GetHeadCommit(projectPath string) (*CommitInfo, error) {
-	repo, err := git.PlainOpen(projectPath)
+	repo, err := s.repository(projectPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -608,6 +660,11 @@ func (s *GitService) GetHeadCommit(projectPath string) (*CommitInfo, error) {
 		parentHashes[i] = hash.String()
 	}
 
+	tags, err := s.tagsByCommit(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
 	return &CommitInfo{
 		Hash:         commit.Hash.String(),
 		Message:      commit.Message,
@@ -615,6 +672,7 @@ func (s *GitService) GetHeadCommit(projectPath string) (*CommitInfo, error) {
 		AuthorEmail:  commit.Author.Email,
 		Date:         commit.Author.When,
 		ParentHashes: parentHashes,
+		Tags:         tags[commit.Hash],
 	}, nil
 }
 
@@ -622,7 +680,7 @@ func (s *GitService) GetHeadCommit(projectPath string) (*CommitInfo, error) {
 // If staged is true, returns the diff between HEAD and staged changes
 // If staged is false, returns the diff between staged/HEAD and working directory
 func (s *GitService) GetFileDiff(projectPath string, filePath string, staged bool) (*FileDiff, error) {
-	repo, err := git.PlainOpen(projectPath)
+	repo, err := s.repository(projectPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -632,306 +690,293 @@ func (s *GitService) GetFileDiff(projectPath string, filePath string, staged boo
 		return nil, fmt.Errorf("failed to get worktree: %w", err)
 	}
 
-	// Get file status to check if it's untracked or binary
-	status, err := worktree.Status()
+	// Get file status to check if it's untracked
+	status, err := s.fileStatus(projectPath, filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get status: %w", err)
+		return nil, err
 	}
 
-	fileStatus := status.File(filePath)
-	if fileStatus.Staging == git.Untracked && staged {
+	untracked := status != nil && status.Worktree == StateUntracked
+	if untracked && staged {
 		return nil, fmt.Errorf("cannot get staged diff for untracked file")
 	}
 
-	// Handle deleted files
-	if fileStatus.Worktree == git.Deleted || fileStatus.Staging == git.Deleted {
-		head, err := repo.Head()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get HEAD: %w", err)
-		}
-
-		commit, err := repo.CommitObject(head.Hash())
-		if err != nil {
-			return nil, fmt.Errorf("failed to get commit: %w", err)
-		}
-
-		tree, err := commit.Tree()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get tree: %w", err)
-		}
-
-		file, err := tree.File(filePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get file from HEAD: %w", err)
-		}
-
-		content, err := file.Contents()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get file contents: %w", err)
-		}
-
-		diff, stats, err := s.generateDiff(content, "", filePath)
-		if err != nil {
-			return nil, err
-		}
+	rules, err := loadAttributeRules(repo)
+	if err != nil {
+		return nil, err
+	}
 
-		return &FileDiff{
-			Path:     filePath,
-			Content:  diff,
-			Stats:    stats,
-			IsBinary: false,
-		}, nil
+	var oldContent, newContent []byte
+	if staged {
+		// Diff between HEAD and index
+		oldContent, newContent, err = stagedContents(repo, filePath)
+	} else {
+		// Diff between index/HEAD and working directory
+		oldContent, newContent, err = workingContents(repo, worktree, filePath, untracked)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.diffContents(rules, filePath, filePath, oldContent, newContent, false)
+}
 
-	// Check if file is binary (only for non-deleted files)
-	fullPath := filepath.Join(projectPath, filePath)
-	isBinary, err := isFileBinary(fullPath)
+// stagedContents returns the HEAD and index versions of a file, nil where it is absent
+func stagedContents(repo *git.Repository, filePath string) ([]byte, []byte, error) {
+	oldContent, err := headContents(repo, filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if file is binary: %w", err)
+		return nil, nil, err
 	}
-	if isBinary {
-		return &FileDiff{
-			Path:     filePath,
-			IsBinary: true,
-		}, nil
+	newContent, _, err := indexContents(repo, filePath)
+	if err != nil {
+		return nil, nil, err
 	}
+	return oldContent, newContent, nil
+}
 
-	var diff string
-	var stats DiffStats
-
-	if staged {
-		// Get diff between HEAD and index
-		diff, stats, err = s.getStagedDiff(repo, worktree, filePath)
-	} else {
-		// Get diff between index/HEAD and working directory
-		diff, stats, err = s.getWorkingDiff(repo, worktree, filePath, fileStatus.Staging == git.Untracked)
+// workingContents returns the index (or HEAD) and working directory versions of a file, nil where it is absent
+func workingContents(repo *git.Repository, worktree *git.Worktree, filePath string, isUntracked bool) ([]byte, []byte, error) {
+	var oldContent []byte
+	if !isUntracked {
+		// Try to get content from index first, then from HEAD
+		content, found, err := indexContents(repo, filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			if content, err = headContents(repo, filePath); err != nil {
+				return nil, nil, err
+			}
+		}
+		oldContent = content
 	}
 
+	newContent, err := os.ReadFile(filepath.Join(worktree.Filesystem.Root(), filePath))
 	if err != nil {
-		return nil, err
+		if !os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("failed to get working file contents: %w", err)
+		}
+		// Deleted from the working directory
+		newContent = nil
 	}
 
-	return &FileDiff{
-		Path:     filePath,
-		Content:  diff,
-		Stats:    stats,
-		IsBinary: false,
-	}, nil
+	return oldContent, newContent, nil
 }
 
-// getStagedDiff returns the diff between HEAD and index
-func (s *GitService) getStagedDiff(repo *git.Repository, worktree *git.Worktree, filePath string) (string, DiffStats, error) {
+// headContents returns the contents of a file in HEAD, nil when there is no HEAD commit or the file is not in it
+func headContents(repo *git.Repository, filePath string) ([]byte, error) {
 	head, err := repo.Head()
 	if err != nil {
 		if err == plumbing.ErrReferenceNotFound {
-			// If no HEAD (new repo), compare with empty tree
-			return s.getDiffWithEmpty(worktree, filePath, true)
+			return nil, nil
 		}
-		return "", DiffStats{}, fmt.Errorf("failed to get HEAD: %w", err)
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
 	}
 
 	commit, err := repo.CommitObject(head.Hash())
 	if err != nil {
-		return "", DiffStats{}, fmt.Errorf("failed to get commit: %w", err)
+		return nil, fmt.Errorf("failed to get commit: %w", err)
 	}
 
-	// Get the tree for HEAD
-	headTree, err := commit.Tree()
+	tree, err := commit.Tree()
 	if err != nil {
-		return "", DiffStats{}, fmt.Errorf("failed to get tree: %w", err)
+		return nil, fmt.Errorf("failed to get tree: %w", err)
 	}
 
-	var oldContent string
-
-	// Get old content from HEAD
-	if headFile, err := headTree.File(filePath); err == nil {
-		oldContent, err = headFile.Contents()
-		if err != nil {
-			return "", DiffStats{}, fmt.Errorf("failed to get HEAD file contents: %w", err)
-		}
+	entry, err := tree.FindEntry(filePath)
+	if err != nil {
+		return nil, nil
 	}
+	return readBlob(repo, entry.Hash)
+}
 
-	// Get index content using the underlying index
+// indexContents returns the contents of a file in the index and whether it is staged
+func indexContents(repo *git.Repository, filePath string) ([]byte, bool, error) {
 	idx, err := repo.Storer.Index()
 	if err != nil {
-		return "", DiffStats{}, fmt.Errorf("failed to get index: %w", err)
+		return nil, false, fmt.Errorf("failed to get index: %w", err)
 	}
 
-	// Find the entry in the index
-	var newContent string
 	for _, entry := range idx.Entries {
 		if entry.Name == filePath {
-			// Get the object from the repository
-			obj, err := repo.BlobObject(entry.Hash)
-			if err != nil {
-				return "", DiffStats{}, fmt.Errorf("failed to get blob object: %w", err)
-			}
-
-			// Read the blob content
-			reader, err := obj.Reader()
-			if err != nil {
-				return "", DiffStats{}, fmt.Errorf("failed to get blob reader: %w", err)
-			}
-			defer reader.Close()
-
-			content, err := io.ReadAll(reader)
-			if err != nil {
-				return "", DiffStats{}, fmt.Errorf("failed to read blob content: %w", err)
-			}
-			newContent = string(content)
-			break
+			content, err := readBlob(repo, entry.Hash)
+			return content, true, err
 		}
 	}
+	return nil, false, nil
+}
 
-	return s.generateDiff(oldContent, newContent, filePath)
+// diffTrees returns the per-file diffs between two trees, either of which may be nil.
+// Renamed files are detected and reported with their previous path.
+func (s *GitService) diffTrees(repo *git.Repository, from, to *object.Tree) ([]FileDiff, error) {
+	return s.selectDiffTrees(repo, from, to, diffSelection{})
 }
 
-// getWorkingDiff returns the diff between index/HEAD and working directory
-func (s *GitService) getWorkingDiff(repo *git.Repository, worktree *git.Worktree, filePath string, isUntracked bool) (string, DiffStats, error) {
-	if isUntracked {
-		return s.getDiffWithEmpty(worktree, filePath, false)
-	}
+// diffSelection chooses what a diff computes
+type diffSelection struct {
+	statsOnly bool   // Compute the stats of the files without their patches
+	path      string // Only diff this file, matched by its path or previous path, when set
+}
 
-	var oldContent string
+// matches tells whether a changed file is selected
+func (sel diffSelection) matches(oldPath, newPath string) bool {
+	return sel.path == "" || sel.path == newPath || sel.path == oldPath
+}
 
-	// Try to get content from index first
-	idx, err := repo.Storer.Index()
+// selectDiffTrees is like diffTrees, computing only the selected diffs
+func (s *GitService) selectDiffTrees(repo *git.Repository, from, to *object.Tree, sel diffSelection) ([]FileDiff, error) {
+	changes, err := object.DiffTreeWithOptions(context.Background(), from, to, object.DefaultDiffTreeOptions)
 	if err != nil {
-		return "", DiffStats{}, fmt.Errorf("failed to get index: %w", err)
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
 	}
 
-	foundInIndex := false
-	for _, entry := range idx.Entries {
-		if entry.Name == filePath {
-			// Get the object from the repository
-			obj, err := repo.BlobObject(entry.Hash)
-			if err != nil {
-				return "", DiffStats{}, fmt.Errorf("failed to get blob object: %w", err)
-			}
-
-			// Read the blob content
-			reader, err := obj.Reader()
-			if err != nil {
-				return "", DiffStats{}, fmt.Errorf("failed to get blob reader: %w", err)
-			}
-			defer reader.Close()
-
-			content, err := io.ReadAll(reader)
-			if err != nil {
-				return "", DiffStats{}, fmt.Errorf("failed to read blob content: %w", err)
-			}
-			oldContent = string(content)
-			foundInIndex = true
-			break
-		}
+	rules, err := loadAttributeRules(repo)
+	if err != nil {
+		return nil, err
 	}
 
-	// If not in index, try HEAD
-	if !foundInIndex {
-		head, err := repo.Head()
-		if err != nil {
-			if err == plumbing.ErrReferenceNotFound {
-				return s.getDiffWithEmpty(worktree, filePath, false)
-			}
-			return "", DiffStats{}, fmt.Errorf("failed to get HEAD: %w", err)
+	diffs := make([]FileDiff, 0, len(changes))
+	for _, change := range changes {
+		if !sel.matches(change.From.Name, change.To.Name) {
+			continue
 		}
-
-		commit, err := repo.CommitObject(head.Hash())
+		fromFile, toFile, err := change.Files()
 		if err != nil {
-			return "", DiffStats{}, fmt.Errorf("failed to get commit: %w", err)
+			return nil, fmt.Errorf("failed to get changed files: %w", err)
 		}
 
-		tree, err := commit.Tree()
+		fileDiff, err := s.diffFiles(rules, change.From.Name, change.To.Name, fromFile, toFile, sel.statsOnly)
 		if err != nil {
-			return "", DiffStats{}, fmt.Errorf("failed to get tree: %w", err)
+			return nil, err
 		}
-
-		if headFile, err := tree.File(filePath); err == nil {
-			oldContent, err = headFile.Contents()
-			if err != nil {
-				return "", DiffStats{}, fmt.Errorf("failed to get HEAD file contents: %w", err)
-			}
+		fileDiff.Status = changeStatus(change)
+		if fileDiff.Status == "R" {
+			fileDiff.OldPath = change.From.Name
 		}
+		diffs = append(diffs, *fileDiff)
 	}
 
-	// Get working directory content
-	newContent, err := s.getFileContents(filepath.Join(worktree.Filesystem.Root(), filePath))
-	if err != nil {
-		return "", DiffStats{}, fmt.Errorf("failed to get working file contents: %w", err)
-	}
-
-	return s.generateDiff(oldContent, newContent, filePath)
+	return diffs, nil
 }
 
-// getDiffWithEmpty returns a diff comparing with an empty file
-func (s *GitService) getDiffWithEmpty(worktree *git.Worktree, filePath string, staged bool) (string, DiffStats, error) {
-	var content string
-	var err error
-
-	// Both staged and unstaged cases read from the same location
-	// since we're dealing with a new file
-	content, err = s.getFileContents(filepath.Join(worktree.Filesystem.Root(), filePath))
-	if err != nil {
-		return "", DiffStats{}, fmt.Errorf("failed to get file contents: %w", err)
+// changeStatus returns the status code of a tree change: "A", "M", "D" or "R"
+func changeStatus(change *object.Change) string {
+	switch {
+	case change.From.Name == "":
+		return "A"
+	case change.To.Name == "":
+		return "D"
+	case change.From.Name != change.To.Name:
+		return "R"
+	default:
+		return "M"
 	}
-
-	return s.generateDiff("", content, filePath)
 }
 
-// generateDiff creates a unified diff from old and new content
-func (s *GitService) generateDiff(oldContent, newContent, filePath string) (string, DiffStats, error) {
-	// For deleted files, show all lines as deleted
-	if newContent == "" && oldContent != "" {
-		lines := strings.Split(strings.TrimSuffix(oldContent, "\n"), "\n")
-		stats := DiffStats{
-			Deleted: len(lines),
-		}
-		var diffOutput strings.Builder
-
-		// Write diff header
-		fmt.Fprintf(&diffOutput, "--- a/%s\n+++ b/%s\n", filePath, filePath)
-		fmt.Fprintf(&diffOutput, "@@ -1,%d +0,0 @@\n", len(lines))
+// diffFiles returns the diff between two versions of a file stored in the object database,
+// either of which may be nil, with an empty path, when the file was added or deleted
+func (s *GitService) diffFiles(rules *attributeRules, oldPath, newPath string, from, to *object.File, statsOnly bool) (*FileDiff, error) {
+	var oldContent, newContent []byte
 
-		// Show each line as deleted
-		for _, line := range lines {
-			fmt.Fprintf(&diffOutput, "-%s\n", line)
+	if from != nil {
+		content, err := from.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file contents: %w", err)
 		}
-
-		return diffOutput.String(), stats, nil
+		oldContent = []byte(content)
+	}
+	if to != nil {
+		content, err := to.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file contents: %w", err)
+		}
+		newContent = []byte(content)
 	}
 
-	// Normalize line endings and split into lines
-	oldLines := strings.Split(strings.TrimSuffix(oldContent, "\n"), "\n")
-	newLines := strings.Split(strings.TrimSuffix(newContent, "\n"), "\n")
+	return s.diffContents(rules, oldPath, newPath, oldContent, newContent, statsOnly)
+}
 
-	// Calculate diffs using go-git/go-diff
-	diffs := diff.Do(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+// diffContextLines is the number of unchanged lines shown around changes, as git does
+const diffContextLines = 3
 
-	stats := DiffStats{}
-	var diffOutput strings.Builder
-
-	// Write diff header
-	fmt.Fprintf(&diffOutput, "--- a/%s\n+++ b/%s\n", filePath, filePath)
+// diffLine is a line of a line-by-line diff, with its line ending
+type diffLine struct {
+	op   byte // ' ' for unchanged, '-' for deleted and '+' for added lines
+	text string
+}
 
-	// Calculate stats and build diff output
-	for _, d := range diffs {
+// diffLines compares two contents line by line
+func diffLines(oldContent, newContent string) []diffLine {
+	var lines []diffLine
+	for _, d := range diff.Do(oldContent, newContent) {
+		op := byte(' ')
 		switch d.Type {
 		case diffmatchpatch.DiffDelete:
-			lines := strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n")
-			stats.Deleted += len(lines)
-			for _, line := range lines {
-				fmt.Fprintf(&diffOutput, "-%s\n", line)
-			}
+			op = '-'
 		case diffmatchpatch.DiffInsert:
-			lines := strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n")
-			stats.Added += len(lines)
-			for _, line := range lines {
-				fmt.Fprintf(&diffOutput, "+%s\n", line)
+			op = '+'
+		}
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				lines = append(lines, diffLine{op: op, text: text})
 			}
-		case diffmatchpatch.DiffEqual:
-			lines := strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n")
-			for _, line := range lines {
-				fmt.Fprintf(&diffOutput, " %s\n", line)
+		}
+	}
+	return lines
+}
+
+// generateDiff creates a unified diff from old and new content, with hunks of
+// changes surrounded by diffContextLines lines of context. The names of both
+// sides are given as they appear in the header, like "a/file" or "/dev/null".
+func (s *GitService) generateDiff(oldContent, newContent, oldName, newName string) (string, DiffStats, error) {
+	lines := diffLines(oldContent, newContent)
+
+	// Line numbers before each line, on both sides
+	oldLine := make([]int, len(lines)+1)
+	newLine := make([]int, len(lines)+1)
+	var changes []int
+	stats := DiffStats{}
+	for i, line := range lines {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		switch line.op {
+		case '-':
+			oldLine[i+1]++
+			stats.Deleted++
+			changes = append(changes, i)
+		case '+':
+			newLine[i+1]++
+			stats.Added++
+			changes = append(changes, i)
+		default:
+			oldLine[i+1]++
+			newLine[i+1]++
+		}
+	}
+	if len(changes) == 0 {
+		return "", stats, nil
+	}
+
+	var diffOutput strings.Builder
+	fmt.Fprintf(&diffOutput, "--- %s\n+++ %s\n", oldName, newName)
+
+	for c := 0; c < len(changes); {
+		// Changes closer than twice the context share a hunk
+		end := changes[c] + 1
+		first := changes[c]
+		for c++; c < len(changes) && changes[c]-end <= 2*diffContextLines; c++ {
+			end = changes[c] + 1
+		}
+		start := max(first-diffContextLines, 0)
+		end = min(end+diffContextLines, len(lines))
+
+		fmt.Fprintf(&diffOutput, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[start], oldLine[end]-oldLine[start]),
+			hunkRange(newLine[start], newLine[end]-newLine[start]))
+		for _, line := range lines[start:end] {
+			diffOutput.WriteByte(line.op)
+			diffOutput.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				diffOutput.WriteString("\n\\ No newline at end of file\n")
 			}
 		}
 	}
@@ -939,6 +984,35 @@ func (s *GitService) generateDiff(oldContent, newContent, filePath string) (stri
 	return diffOutput.String(), stats, nil
 }
 
+// hunkRange formats the range of lines of a hunk on one side, given the number of
+// lines before it, like git: the line count is left out when it is one, and empty
+// ranges start at the line before them
+func hunkRange(before int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return strconv.Itoa(before + 1)
+	default:
+		return fmt.Sprintf("%d,%d", before+1, count)
+	}
+}
+
+// diffStats counts the lines added and deleted between two contents, as generateDiff
+// does, without building the patch
+func diffStats(oldContent, newContent string) DiffStats {
+	stats := DiffStats{}
+	for _, line := range diffLines(oldContent, newContent) {
+		switch line.op {
+		case '-':
+			stats.Deleted++
+		case '+':
+			stats.Added++
+		}
+	}
+	return stats
+}
+
 // getFileContents reads a file's contents
 func (s *GitService) getFileContents(path string) (string, error) {
 	content, err := os.ReadFile(path)
@@ -947,21 +1021,3 @@ func (s *GitService) getFileContents(path string) (string, error) {
 	}
 	return string(content), nil
 }
-
-// isFileBinary checks if a file is binary
-func isFileBinary(path string) (bool, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return false, err
-	}
-	defer file.Close()
-
-	// Read first 512 bytes to check content type
-	buffer := make([]byte, 512)
-	n, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
-		return false, err
-	}
-
-	return strings.Contains(http.DetectContentType(buffer[:n]), "application/octet-stream"), nil
-}
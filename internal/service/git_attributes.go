@@ -0,0 +1,430 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+)
+
+// lfsPointerVersion is the first line of a Git LFS pointer file
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// FileAttributes are the .gitattributes settings of a file that affect how it is diffed
+type FileAttributes struct {
+	Path       string `json:"path"`
+	Binary     bool   `json:"binary"`     // Marked binary, with -diff or with a diff driver that is binary
+	TextDiff   bool   `json:"textDiff"`   // Marked diff, always shown as text
+	Text       string `json:"text"`       // "set", "unset", "auto" or empty when unspecified
+	Eol        string `json:"eol"`        // "lf" or "crlf", empty when unspecified
+	DiffDriver string `json:"diffDriver"` // Custom diff driver set with diff=<name>
+	Generated  bool   `json:"generated"`  // Marked linguist-generated
+	LFS        bool   `json:"lfs"`        // Stored with Git LFS (filter=lfs)
+	Encoding   string `json:"encoding"`   // working-tree-encoding
+}
+
+// LFSPointer identifies a file stored with Git LFS
+type LFSPointer struct {
+	Oid  string `json:"oid"`  // SHA-256 of the file contents
+	Size int64  `json:"size"` // Size of the file contents in bytes
+}
+
+// LFSDiff describes a change to a file stored with Git LFS, whose contents are not in the repository
+type LFSDiff struct {
+	Old *LFSPointer `json:"old,omitempty"` // Nil when the file was added
+	New *LFSPointer `json:"new,omitempty"` // Nil when the file was deleted
+}
+
+// diffDriver is a custom diff driver from the diff.<name> configuration
+type diffDriver struct {
+	textconv string // Command converting the file to text before diffing
+	binary   bool
+}
+
+// attributeRules resolves the .gitattributes of paths in a working tree. Like
+// git, the global attributes file has the lowest priority, then .gitattributes
+// files from the root down to the file, then $GIT_DIR/info/attributes.
+type attributeRules struct {
+	root    string
+	global  []gitattributes.MatchAttribute
+	info    []gitattributes.MatchAttribute
+	dirs    map[string][]gitattributes.MatchAttribute // .gitattributes files per directory, read on first use
+	macros  map[string][]gitattributes.Attribute
+	drivers map[string]diffDriver
+}
+
+// GetFileAttributes returns the .gitattributes settings of a file
+func (s *GitService) GetFileAttributes(projectPath string, filePath string) (*FileAttributes, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	rules, err := loadAttributeRules(repo)
+	if err != nil {
+		return nil, err
+	}
+	attrs := rules.attributes(filepath.ToSlash(filePath))
+	return &attrs, nil
+}
+
+// loadAttributeRules reads the attribute files and diff drivers of a repository
+func loadAttributeRules(repo *git.Repository) (*attributeRules, error) {
+	rules := &attributeRules{
+		dirs: make(map[string][]gitattributes.MatchAttribute),
+		macros: map[string][]gitattributes.Attribute{
+			"binary": parseAttributeList("-diff -merge -text"),
+		},
+		drivers: make(map[string]diffDriver),
+	}
+	if worktree, err := repo.Worktree(); err == nil {
+		rules.root = worktree.Filesystem.Root()
+	}
+
+	cfg, err := repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git configuration: %w", err)
+	}
+	for _, subsection := range cfg.Raw.Section("diff").Subsections {
+		binary, _ := strconv.ParseBool(subsection.Option("binary"))
+		rules.drivers[subsection.Name] = diffDriver{
+			textconv: subsection.Option("textconv"),
+			binary:   binary,
+		}
+	}
+
+	globalFile := cfg.Raw.Section("core").Option("attributesFile")
+	switch {
+	case strings.HasPrefix(globalFile, "~/"):
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			globalFile = filepath.Join(homeDir, globalFile[2:])
+		}
+	case globalFile == "":
+		if configDir, err := os.UserConfigDir(); err == nil {
+			globalFile = filepath.Join(configDir, "git", "attributes")
+		}
+	}
+	if globalFile != "" {
+		if rules.global, err = rules.readFile(globalFile, nil, true); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := gitDir(repo)
+	if err != nil {
+		return nil, err
+	}
+	if rules.info, err = rules.readFile(filepath.Join(commonGitDir(dir), "info", "attributes"), nil, true); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// readFile parses an attributes file. Macros are only allowed at the top level,
+// like git, and invalid lines are skipped.
+func (r *attributeRules) readFile(file string, domain []string, allowMacro bool) ([]gitattributes.MatchAttribute, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var rules []gitattributes.MatchAttribute
+	for _, line := range strings.Split(string(content), "\n") {
+		rule, err := gitattributes.ParseAttributesLine(line, domain, allowMacro)
+		if err != nil || rule.Name == "" {
+			continue
+		}
+		if rule.Pattern == nil {
+			r.macros[rule.Name] = rule.Attributes
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// dirRules returns the rules of the .gitattributes file of a working tree directory
+func (r *attributeRules) dirRules(dir string) []gitattributes.MatchAttribute {
+	if rules, ok := r.dirs[dir]; ok {
+		return rules
+	}
+
+	var domain []string
+	if dir != "" {
+		domain = strings.Split(dir, "/")
+	}
+	// Attributes that cannot be read are ignored, the diff is still shown
+	rules, _ := r.readFile(filepath.Join(r.root, filepath.FromSlash(dir), ".gitattributes"), domain, dir == "")
+	r.dirs[dir] = rules
+	return rules
+}
+
+// attributes returns the attributes of a file
+func (r *attributeRules) attributes(file string) FileAttributes {
+	parts := strings.Split(file, "/")
+
+	stack := append([]gitattributes.MatchAttribute{}, r.global...)
+	if r.root != "" {
+		for i := 0; i < len(parts); i++ {
+			stack = append(stack, r.dirRules(path.Join(parts[:i]...))...)
+		}
+	}
+	stack = append(stack, r.info...)
+
+	// Later rules take precedence
+	values := make(map[string]gitattributes.Attribute)
+	for _, rule := range stack {
+		if !rule.Pattern.Match(parts) {
+			continue
+		}
+		for _, attr := range rule.Attributes {
+			if attr.IsSet() {
+				for _, expanded := range r.macros[attr.Name()] {
+					values[expanded.Name()] = expanded
+				}
+			}
+			if attr.IsUnspecified() {
+				delete(values, attr.Name())
+				continue
+			}
+			values[attr.Name()] = attr
+		}
+	}
+
+	attrs := FileAttributes{Path: file}
+	if diff, ok := values["diff"]; ok {
+		switch {
+		case diff.IsUnset():
+			attrs.Binary = true
+		case diff.IsSet():
+			attrs.TextDiff = true
+		case diff.IsValueSet():
+			attrs.DiffDriver = diff.Value()
+			attrs.Binary = r.drivers[diff.Value()].binary
+		}
+	}
+	if text, ok := values["text"]; ok {
+		switch {
+		case text.IsSet():
+			attrs.Text = "set"
+		case text.IsUnset():
+			attrs.Text = "unset"
+		case text.IsValueSet() && text.Value() == "auto":
+			attrs.Text = "auto"
+		}
+	}
+	if eol, ok := values["eol"]; ok && eol.IsValueSet() {
+		attrs.Eol = eol.Value()
+	}
+	if generated, ok := values["linguist-generated"]; ok {
+		attrs.Generated = generated.IsSet() || generated.IsValueSet() && generated.Value() == "true"
+	}
+	if filter, ok := values["filter"]; ok && filter.IsValueSet() {
+		attrs.LFS = filter.Value() == "lfs"
+	}
+	if encoding, ok := values["working-tree-encoding"]; ok && encoding.IsValueSet() {
+		attrs.Encoding = strings.ToUpper(encoding.Value())
+	}
+	return attrs
+}
+
+// parseAttributeList parses the attributes of a macro definition
+func parseAttributeList(list string) []gitattributes.Attribute {
+	rule, err := gitattributes.ParseAttributesLine("[attr]macro "+list, nil, true)
+	if err != nil {
+		return nil
+	}
+	return rule.Attributes
+}
+
+// diffContents builds the diff of a file between two versions, nil when the file
+// is absent on that side, following the attributes of the file: LFS pointers are
+// reported as such, text conversion drivers are run, binary files are not diffed
+// and line endings are normalized for text files. With statsOnly, only the stats
+// of text files are computed, not their patch. The paths differ for renamed files
+// and may be empty on the side the file is absent from.
+func (s *GitService) diffContents(rules *attributeRules, oldPath, newPath string, oldContent, newContent []byte, statsOnly bool) (*FileDiff, error) {
+	filePath := newPath
+	if filePath == "" {
+		filePath = oldPath
+	}
+
+	// Like git, the patch header names /dev/null for the side the file is absent from
+	oldName, newName := "a/"+oldPath, "b/"+newPath
+	if oldContent == nil {
+		oldName = "/dev/null"
+	}
+	if newContent == nil {
+		newName = "/dev/null"
+	}
+
+	attrs := rules.attributes(filePath)
+	fileDiff := &FileDiff{Path: filePath, Generated: attrs.Generated}
+
+	oldPointer := lfsPointerOf(oldContent, attrs.LFS)
+	newPointer := lfsPointerOf(newContent, attrs.LFS)
+	if oldPointer != nil || newPointer != nil {
+		fileDiff.LFS = &LFSDiff{Old: oldPointer, New: newPointer}
+		return fileDiff, nil
+	}
+
+	if driver := rules.drivers[attrs.DiffDriver]; driver.textconv != "" {
+		var err error
+		if oldContent, err = textconv(rules.root, driver.textconv, oldContent); err != nil {
+			return nil, err
+		}
+		if newContent, err = textconv(rules.root, driver.textconv, newContent); err != nil {
+			return nil, err
+		}
+	} else {
+		oldContent = decodeText(oldContent, attrs.Encoding)
+		newContent = decodeText(newContent, attrs.Encoding)
+		if attrs.Binary || !attrs.TextDiff && (isBinaryContent(oldContent) || isBinaryContent(newContent)) {
+			fileDiff.IsBinary = true
+			return fileDiff, nil
+		}
+	}
+
+	if attrs.Text != "unset" && (attrs.Text != "" || attrs.Eol != "") {
+		// The repository stores text files with LF endings, the working tree may not
+		oldContent = bytes.ReplaceAll(oldContent, []byte("\r\n"), []byte("\n"))
+		newContent = bytes.ReplaceAll(newContent, []byte("\r\n"), []byte("\n"))
+	}
+
+	if statsOnly {
+		fileDiff.Stats = diffStats(string(oldContent), string(newContent))
+		return fileDiff, nil
+	}
+
+	var err error
+	fileDiff.Content, fileDiff.Stats, err = s.generateDiff(string(oldContent), string(newContent), oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+	return fileDiff, nil
+}
+
+// lfsPointerOf returns the LFS pointer of a file version. Pointer files are parsed;
+// the contents of files tracked with LFS that are not pointers, because they were
+// checked out through the LFS filter, are hashed the way the filter stores them.
+func lfsPointerOf(content []byte, tracked bool) *LFSPointer {
+	if content == nil {
+		return nil
+	}
+	if pointer := parseLFSPointer(content); pointer != nil {
+		return pointer
+	}
+	if !tracked || len(content) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	return &LFSPointer{Oid: hex.EncodeToString(sum[:]), Size: int64(len(content))}
+}
+
+// parseLFSPointer parses a Git LFS pointer file, returning nil for other contents
+func parseLFSPointer(content []byte) *LFSPointer {
+	// Pointer files are small, anything bigger is real content
+	if len(content) > 1024 || !bytes.HasPrefix(content, []byte(lfsPointerVersion+"\n")) {
+		return nil
+	}
+
+	pointer := &LFSPointer{Size: -1}
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "oid":
+			pointer.Oid = strings.TrimPrefix(value, "sha256:")
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil
+			}
+			pointer.Size = size
+		}
+	}
+	if pointer.Oid == "" || pointer.Size < 0 {
+		return nil
+	}
+	return pointer
+}
+
+// textconv converts a file version to text with the command of a diff driver
+func textconv(root string, command string, content []byte) ([]byte, error) {
+	if content == nil {
+		return nil, nil
+	}
+
+	file, err := os.CreateTemp("", "textconv-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(content)
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	// git runs the command through the shell with the file as its argument
+	cmd := exec.Command("sh", "-c", command+` "$@"`, command, file.Name())
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("textconv %s failed: %s", command, msg)
+	}
+	return out, nil
+}
+
+// decodeText converts UTF-16 contents to UTF-8, recognizing them by their byte order
+// mark or, when the working-tree-encoding attribute asks for it, by their NUL bytes.
+// Other contents are returned as is.
+func decodeText(content []byte, encoding string) []byte {
+	data := content
+	bigEndian := false
+	switch {
+	case bytes.HasPrefix(content, []byte{0xff, 0xfe}):
+		data = content[2:]
+	case bytes.HasPrefix(content, []byte{0xfe, 0xff}):
+		data = content[2:]
+		bigEndian = true
+	case strings.HasPrefix(encoding, "UTF-16") && bytes.IndexByte(content, 0) >= 0:
+		// Without a byte order mark, UTF-16 is big endian
+		bigEndian = encoding != "UTF-16LE"
+	default:
+		return content
+	}
+	if len(data)%2 != 0 {
+		return content
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(units)))
+}
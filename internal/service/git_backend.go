@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Backend names used in the git section of the configuration
+const (
+	BackendGoGit = "go-git" // Pure Go implementation
+	BackendCLI   = "cli"    // System git binary
+)
+
+// Operations whose backend can be chosen in the configuration
+const (
+	OpStatus     = "status"
+	OpRebase     = "rebase"
+	OpCherryPick = "cherryPick"
+	OpRevert     = "revert"
+)
+
+// defaultBackends lists the backend used for each operation unless configured otherwise.
+// go-git has no rebase, cherry-pick or revert, so those default to the git CLI.
+var defaultBackends = map[string]string{
+	OpStatus:     BackendGoGit,
+	OpRebase:     BackendCLI,
+	OpCherryPick: BackendCLI,
+	OpRevert:     BackendCLI,
+}
+
+// errNotSupported is returned by backends for operations they cannot perform
+var errNotSupported = errors.New("operation not supported by the go-git backend, configure the cli backend instead")
+
+// gitBackend performs the repository operations that can be delegated to
+// different implementations. All backends return the same result types.
+type gitBackend interface {
+	// Name returns the backend name used in the configuration
+	Name() string
+	// Status returns the staged, unstaged and untracked files of the repository
+	Status(projectPath string, opts StatusOptions) ([]StatusEntry, error)
+	// Rebase starts an interactive rebase of the current branch onto base, or from the
+	// root commit when base is empty, executing the todo list prepared in todoDir
+	Rebase(projectPath string, base string, todoDir string) error
+	// ContinueRebase resumes a stopped rebase, skipping the current step when skip is set
+	ContinueRebase(projectPath string, skip bool) error
+	// AbortRebase stops the rebase in progress and restores the branch
+	AbortRebase(projectPath string) error
+	// CherryPick applies the changes of the given commits on top of the current branch
+	CherryPick(projectPath string, commits []string, noCommit bool) error
+	// Revert creates commits reverting the changes of the given commits
+	Revert(projectPath string, commits []string, noCommit bool) error
+}
+
+// newBackends resolves the backend of every operation from the configured names
+func newBackends(configured map[string]string, repos *repoCache) map[string]gitBackend {
+	available := map[string]gitBackend{
+		BackendGoGit: &goGitBackend{repos: repos},
+		BackendCLI:   &cliBackend{},
+	}
+
+	// The configuration loader lowercases keys, so operations are matched case-insensitively
+	names := make(map[string]string, len(configured))
+	for op, name := range configured {
+		names[strings.ToLower(op)] = name
+	}
+
+	backends := make(map[string]gitBackend, len(defaultBackends))
+	for op, name := range defaultBackends {
+		if configuredName, ok := names[strings.ToLower(op)]; ok {
+			if _, known := available[configuredName]; known {
+				name = configuredName
+			} else {
+				log.Printf("[GitService] Unknown backend %q for %s, using %s", configuredName, op, name)
+			}
+		}
+		backends[op] = available[name]
+	}
+
+	return backends
+}
+
+// backend returns the backend configured for an operation
+func (s *GitService) backend(op string) gitBackend {
+	return s.backends[op]
+}
+
+// goGitBackend implements repository operations with go-git
+type goGitBackend struct {
+	repos *repoCache
+}
+
+// Name returns the backend name used in the configuration
+func (b *goGitBackend) Name() string {
+	return BackendGoGit
+}
+
+// Status computes the repository status with go-git
+func (b *goGitBackend) Status(projectPath string, opts StatusOptions) ([]StatusEntry, error) {
+	entry, err := b.repos.get(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	repo, err := entry.repository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	return repositoryStatus(repo, opts)
+}
+
+// Rebase is not supported by go-git
+func (b *goGitBackend) Rebase(projectPath string, base string, todoDir string) error {
+	return errNotSupported
+}
+
+// ContinueRebase is not supported by go-git
+func (b *goGitBackend) ContinueRebase(projectPath string, skip bool) error {
+	return errNotSupported
+}
+
+// AbortRebase is not supported by go-git
+func (b *goGitBackend) AbortRebase(projectPath string) error {
+	return errNotSupported
+}
+
+// CherryPick is not supported by go-git
+func (b *goGitBackend) CherryPick(projectPath string, commits []string, noCommit bool) error {
+	return errNotSupported
+}
+
+// Revert is not supported by go-git
+func (b *goGitBackend) Revert(projectPath string, commits []string, noCommit bool) error {
+	return errNotSupported
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// maxBlameCacheEntries bounds the number of cached blame results
+const maxBlameCacheEntries = 64
+
+// BlameLine represents the origin of a single line of a file
+type BlameLine struct {
+	Line        int       `json:"line"`        // 1-based line number
+	Content     string    `json:"content"`     // Line text
+	Hash        string    `json:"hash"`        // Commit that last changed the line, empty if uncommitted
+	Author      string    `json:"author"`      // Author name
+	AuthorEmail string    `json:"authorEmail"` // Author email
+	Date        time.Time `json:"date"`        // Author date
+	Summary     string    `json:"summary"`     // First line of the commit message
+	Uncommitted bool      `json:"uncommitted"` // Whether the line only exists in the working copy
+}
+
+// BlameResult contains the blame information for a file
+type BlameResult struct {
+	Path     string      `json:"path"`     // File path relative to repository root
+	Revision string      `json:"revision"` // Commit the blame was computed at
+	Lines    []BlameLine `json:"lines"`
+}
+
+// GetBlame returns per-line blame information for a file.
+// With an empty revision the file is blamed at HEAD and the working copy is
+// overlaid on top of it, so lines that were changed but not committed yet are
+// reported as uncommitted. Any other revision blames the file as stored in that commit.
+func (s *GitService) GetBlame(projectPath string, filePath string, revision string) (*BlameResult, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	repo, err := s.repository(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	if revision != "" {
+		commit, err := resolveCommit(repo, revision)
+		if err != nil {
+			return nil, err
+		}
+		return s.blameAt(repo, absPath, commit, filePath)
+	}
+
+	workingContent, err := s.getFileContents(filepath.Join(absPath, filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// No commits yet, every line is uncommitted
+			return &BlameResult{Path: filePath, Lines: uncommittedLines(splitLines(workingContent))}, nil
+		}
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit: %w", err)
+	}
+
+	committed, err := s.blameAt(repo, absPath, commit, filePath)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			// The file is new in the working copy
+			return &BlameResult{Path: filePath, Revision: commit.Hash.String(), Lines: uncommittedLines(splitLines(workingContent))}, nil
+		}
+		return nil, err
+	}
+
+	return overlayWorkingCopy(committed, workingContent), nil
+}
+
+// blameAt returns the blame of a file at a commit, using the cache when possible
+func (s *GitService) blameAt(repo *git.Repository, absPath string, commit *object.Commit, filePath string) (*BlameResult, error) {
+	key := absPath + "\x00" + commit.Hash.String() + "\x00" + filePath
+
+	s.blameLock.Lock()
+	cached, ok := s.blameCache[key]
+	s.blameLock.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	blame, err := git.Blame(commit, filePath)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to blame file: %w", err)
+	}
+
+	// Look up each commit once to get its summary
+	summaries := make(map[plumbing.Hash]string)
+	result := &BlameResult{
+		Path:     filePath,
+		Revision: commit.Hash.String(),
+		Lines:    make([]BlameLine, len(blame.Lines)),
+	}
+	for i, line := range blame.Lines {
+		summary, ok := summaries[line.Hash]
+		if !ok {
+			if c, err := repo.CommitObject(line.Hash); err == nil {
+				summary, _, _ = strings.Cut(strings.TrimSpace(c.Message), "\n")
+			}
+			summaries[line.Hash] = summary
+		}
+
+		result.Lines[i] = BlameLine{
+			Line:        i + 1,
+			Content:     line.Text,
+			Hash:        line.Hash.String(),
+			Author:      line.AuthorName,
+			AuthorEmail: line.Author,
+			Date:        line.Date,
+			Summary:     summary,
+		}
+	}
+
+	s.blameLock.Lock()
+	if len(s.blameCache) >= maxBlameCacheEntries {
+		// Results are keyed by commit so they never go stale, just start over when full
+		s.blameCache = make(map[string]*BlameResult)
+	}
+	s.blameCache[key] = result
+	s.blameLock.Unlock()
+
+	return result, nil
+}
+
+// overlayWorkingCopy maps the blame of the committed file onto its working copy.
+// Lines unchanged since the commit keep their blame, other lines are marked as uncommitted.
+func overlayWorkingCopy(committed *BlameResult, workingContent string) *BlameResult {
+	committedLines := make([]string, len(committed.Lines))
+	for i, line := range committed.Lines {
+		committedLines[i] = line.Content
+	}
+	workingLines := splitLines(workingContent)
+
+	result := &BlameResult{
+		Path:     committed.Path,
+		Revision: committed.Revision,
+		Lines:    make([]BlameLine, 0, len(workingLines)),
+	}
+
+	diffs := diff.Do(joinLines(committedLines), joinLines(workingLines))
+	oldIdx, newIdx := 0, 0
+	for _, d := range diffs {
+		count := countLines(d.Text)
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			for i := 0; i < count && oldIdx < len(committed.Lines) && newIdx < len(workingLines); i++ {
+				line := committed.Lines[oldIdx]
+				line.Line = newIdx + 1
+				result.Lines = append(result.Lines, line)
+				oldIdx++
+				newIdx++
+			}
+		case diffmatchpatch.DiffDelete:
+			oldIdx += count
+		case diffmatchpatch.DiffInsert:
+			for i := 0; i < count && newIdx < len(workingLines); i++ {
+				result.Lines = append(result.Lines, BlameLine{
+					Line:        newIdx + 1,
+					Content:     workingLines[newIdx],
+					Uncommitted: true,
+				})
+				newIdx++
+			}
+		}
+	}
+
+	return result
+}
+
+// uncommittedLines returns blame lines marking every line as uncommitted
+func uncommittedLines(lines []string) []BlameLine {
+	result := make([]BlameLine, len(lines))
+	for i, line := range lines {
+		result[i] = BlameLine{
+			Line:        i + 1,
+			Content:     line,
+			Uncommitted: true,
+		}
+	}
+	return result
+}
+
+// splitLines splits content into lines, ignoring the final line terminator
+func splitLines(content string) []string {
+	if content == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// joinLines joins lines so that each one, including the last, ends with a newline
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// countLines returns the number of lines in a chunk of a line-based diff
+func countLines(text string) int {
+	count := strings.Count(text, "\n")
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// repoCache keeps the cached status, tags and file system watcher of each project.
+// Repositories themselves are opened again by every call: a go-git repository is
+// not safe for concurrent use, and calls from the frontend, status refreshes of the
+// watcher and searches run at the same time.
+type repoCache struct {
+	lock    sync.Mutex
+	entries map[string]*cachedRepo
+}
+
+// cachedRepo is a repository with its cached status
+type cachedRepo struct {
+	path      string
+	gitDir    string
+	commonDir string
+
+	lock sync.Mutex
+
+	// Status and the repository state it was computed for
+	statusLock  sync.Mutex
+	status      []StatusEntry
+	statusKey   string
+	generation  uint64 // Incremented on every file system change
+	hasStatus   bool
+	watchOnce   sync.Once
+	watcher     *repoWatcher
+	refreshWait *time.Timer
+
+	// Tags by commit and the state of the tag references they were read from
+	tagsLock sync.Mutex
+	tags     map[plumbing.Hash][]string
+	tagsKey  string
+}
+
+func newRepoCache() *repoCache {
+	return &repoCache{entries: make(map[string]*cachedRepo)}
+}
+
+// get returns the cached repository of a project, looking it up on first use
+func (c *repoCache) get(projectPath string) (*cachedRepo, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if entry, ok := c.entries[absPath]; ok {
+		if _, err := os.Stat(entry.gitDir); err == nil {
+			return entry, nil
+		}
+		// The repository was removed
+		entry.close()
+		delete(c.entries, absPath)
+	}
+
+	repo, err := openRepository(absPath)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := gitDir(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cachedRepo{
+		path:      absPath,
+		gitDir:    dir,
+		commonDir: commonGitDir(dir),
+	}
+	c.entries[absPath] = entry
+	return entry, nil
+}
+
+// remove closes and forgets the repository of a project
+func (c *repoCache) remove(projectPath string) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if entry, ok := c.entries[absPath]; ok {
+		entry.close()
+		delete(c.entries, absPath)
+	}
+}
+
+// closeAll closes every cached repository
+func (c *repoCache) closeAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for path, entry := range c.entries {
+		entry.close()
+		delete(c.entries, path)
+	}
+}
+
+// repository opens the repository. The returned repository belongs to the caller
+// and must not be shared with other goroutines.
+func (r *cachedRepo) repository() (*git.Repository, error) {
+	return openRepository(r.path)
+}
+
+// stateKey identifies the repository state a status is valid for: the index,
+// HEAD and the file system changes seen so far
+func (r *cachedRepo) stateKey() string {
+	r.lock.Lock()
+	key := fmt.Sprintf("%d", r.generation)
+	r.lock.Unlock()
+
+	if info, err := os.Stat(filepath.Join(r.gitDir, "index")); err == nil {
+		key += fmt.Sprintf(":%d:%d", info.Size(), info.ModTime().UnixNano())
+	}
+	if repo, err := r.repository(); err == nil {
+		if head, err := repo.Head(); err == nil {
+			key += ":" + head.Name().String() + ":" + head.Hash().String()
+		}
+	}
+	return key
+}
+
+// tagsByCommit returns the tag names pointing at each commit, read again only when
+// tag references changed. The map is shared and must not be modified.
+func (r *cachedRepo) tagsByCommit() (map[plumbing.Hash][]string, error) {
+	key := r.tagsStateKey()
+
+	r.tagsLock.Lock()
+	defer r.tagsLock.Unlock()
+	if r.tags != nil && key == r.tagsKey {
+		return r.tags, nil
+	}
+
+	repo, err := r.repository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	tags, err := tagsByCommit(repo)
+	if err != nil {
+		return nil, err
+	}
+	r.tags = tags
+	r.tagsKey = key
+	return tags, nil
+}
+
+// tagsStateKey identifies the state of the tag references: the packed references
+// and the directories of loose tags, whose modification time changes when a tag
+// is created, moved or deleted
+func (r *cachedRepo) tagsStateKey() string {
+	var key strings.Builder
+	if info, err := os.Stat(filepath.Join(r.commonDir, "packed-refs")); err == nil {
+		fmt.Fprintf(&key, "%d:%d", info.Size(), info.ModTime().UnixNano())
+	}
+	filepath.WalkDir(filepath.Join(r.commonDir, "refs", "tags"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			fmt.Fprintf(&key, ":%s:%d", path, info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	return key.String()
+}
+
+// invalidate marks the cached status as outdated
+func (r *cachedRepo) invalidate() {
+	r.lock.Lock()
+	r.generation++
+	r.lock.Unlock()
+}
+
+// close stops watching the repository
+func (r *cachedRepo) close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.watcher != nil {
+		r.watcher.close()
+		r.watcher = nil
+	}
+	if r.refreshWait != nil {
+		r.refreshWait.Stop()
+	}
+}
+
+// repository opens the repository of a project for the calling goroutine
+func (s *GitService) repository(projectPath string) (*git.Repository, error) {
+	entry, err := s.repos.get(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return entry.repository()
+}
+
+// tagsByCommit returns the tag names pointing at each commit of a project, cached
+// until tags change. The map is shared and must not be modified.
+func (s *GitService) tagsByCommit(projectPath string) (map[plumbing.Hash][]string, error) {
+	entry, err := s.repos.get(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	return entry.tagsByCommit()
+}
+
+// status returns the status of a repository, computing it only when the index,
+// HEAD or the working tree changed since it was last computed. Changes are
+// announced with a status changed event.
+func (s *GitService) status(projectPath string) ([]StatusEntry, error) {
+	entry, err := s.repos.get(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	entry.watchOnce.Do(func() {
+		s.watch(entry)
+	})
+
+	entry.statusLock.Lock()
+	defer entry.statusLock.Unlock()
+
+	entry.lock.Lock()
+	watching := entry.watcher != nil
+	entry.lock.Unlock()
+
+	// Without a watcher, changes to the working tree go unnoticed, so nothing is reused
+	key := entry.stateKey()
+	if watching && entry.hasStatus && key == entry.statusKey {
+		return entry.status, nil
+	}
+
+	entries, err := s.backend(OpStatus).Status(entry.path, StatusOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []StatusEntry{}
+	}
+	sortStatusEntries(entries)
+
+	changed := entry.hasStatus && !reflect.DeepEqual(entry.status, entries)
+	entry.status = entries
+	entry.statusKey = key
+	entry.hasStatus = true
+
+	if changed {
+		s.emit(EventStatusChanged, StatusChangedEvent{ProjectPath: entry.path, Files: fileStatuses(entries)})
+	}
+	return entries, nil
+}
+
+// fileStatus returns the status entry of a file, nil when unchanged
+func (s *GitService) fileStatus(projectPath string, file string) (*StatusEntry, error) {
+	entries, err := s.status(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file = filepath.ToSlash(file)
+	for i := range entries {
+		if entries[i].Path == file {
+			return &entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// invalidateStatus discards the cached status of a project after the service changed its working tree
+func (s *GitService) invalidateStatus(projectPath string) {
+	if entry, err := s.repos.get(projectPath); err == nil {
+		entry.invalidate()
+	}
+}
+
+// CloseRepository releases the cached repository of a project and stops watching it
+func (s *GitService) CloseRepository(projectPath string) {
+	s.repos.remove(projectPath)
+}
+
+// Close releases every cached repository
+func (s *GitService) Close() {
+	s.repos.closeAll()
+}
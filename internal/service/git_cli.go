@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// runGit executes the system git binary inside the given repository and returns its standard output.
+// It is used for operations that go-git does not support.
+func runGit(projectPath string, args ...string) (string, error) {
+	return runGitEnv(projectPath, nil, args...)
+}
+
+// runGitEnv is like runGit but adds environment variables ("NAME=value") to the git process
+func runGitEnv(projectPath string, env []string, args ...string) (string, error) {
+	return runGitInput(projectPath, env, nil, args...)
+}
+
+// runGitInput is like runGitEnv but feeds input to the standard input of the git process
+func runGitInput(projectPath string, env []string, input []byte, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = projectPath
+	// Force stable, non-interactive output so it can be parsed reliably
+	cmd.Env = append(os.Environ(), "LC_ALL=C", "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, env...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return stdout.String(), fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+
+	return stdout.String(), nil
+}
+
+// cliBackend implements repository operations with the system git binary
+type cliBackend struct{}
+
+// Name returns the backend name used in the configuration
+func (b *cliBackend) Name() string {
+	return BackendCLI
+}
+
+// Status parses the output of "git status --porcelain=v2" into status entries
+func (b *cliBackend) Status(projectPath string, opts StatusOptions) ([]StatusEntry, error) {
+	args := []string{"status", "--porcelain=v2", "-z", "--untracked-files=all", "--renames"}
+	if opts.Ignored {
+		args = append(args, "--ignored=traditional")
+	}
+	out, err := runGit(projectPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	var entries []StatusEntry
+	records := strings.Split(out, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
+			continue
+		}
+
+		switch record[0] {
+		case '?', '!':
+			state := string(record[0])
+			entries = append(entries, StatusEntry{Path: record[2:], Index: state, Worktree: state})
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) < 11 {
+				continue
+			}
+			entries = append(entries, StatusEntry{
+				Path:     fields[10],
+				Index:    fields[1][:1],
+				Worktree: fields[1][1:],
+				Unmerged: true,
+			})
+		case '1', '2':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>, followed by the original path
+			fieldCount := 9
+			if record[0] == '2' {
+				fieldCount = 10
+			}
+			fields := strings.SplitN(record, " ", fieldCount)
+			if len(fields) < fieldCount {
+				continue
+			}
+
+			entry := StatusEntry{
+				Path:         fields[fieldCount-1],
+				Index:        fields[1][:1],
+				Worktree:     fields[1][1:],
+				HeadMode:     fields[3],
+				IndexMode:    fields[4],
+				WorktreeMode: fields[5],
+				HeadHash:     fields[6],
+				IndexHash:    fields[7],
+				Submodule:    porcelainSubmodule(fields[2]),
+			}
+			if record[0] == '2' {
+				entry.Score, _ = strconv.Atoi(fields[8][1:])
+				if i+1 < len(records) {
+					i++
+					entry.OrigPath = records[i]
+				}
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// porcelainSubmodule parses the "S<c><m><u>" submodule field of a porcelain v2 record, "N..." for other files
+func porcelainSubmodule(field string) *SubmoduleState {
+	if len(field) != 4 || field[0] != 'S' {
+		return nil
+	}
+	return &SubmoduleState{
+		CommitChanged: field[1] == 'C',
+		Modified:      field[2] == 'M',
+		Untracked:     field[3] == 'U',
+	}
+}
+
+// Rebase starts an interactive rebase executing the todo list prepared in todoDir.
+// Local changes are stashed for the duration of the rebase.
+func (b *cliBackend) Rebase(projectPath string, base string, todoDir string) error {
+	args := []string{"rebase", "--interactive", "--autostash"}
+	if base == "" {
+		args = append(args, "--root")
+	} else {
+		args = append(args, base)
+	}
+
+	// git runs the sequence editor with the path of its todo file as argument
+	sequenceEditor := `sh -c 'cp "$0"/* "$(dirname "$1")"/' ` + shellQuote(todoDir)
+	_, err := runGitEnv(projectPath, []string{"GIT_SEQUENCE_EDITOR=" + sequenceEditor, "GIT_EDITOR=true"}, args...)
+	return err
+}
+
+// ContinueRebase resumes a stopped rebase, skipping the current step when skip is set
+func (b *cliBackend) ContinueRebase(projectPath string, skip bool) error {
+	action := "--continue"
+	if skip {
+		action = "--skip"
+	}
+	_, err := runGitEnv(projectPath, []string{"GIT_EDITOR=true"}, "rebase", action)
+	return err
+}
+
+// AbortRebase stops the rebase in progress and restores the branch
+func (b *cliBackend) AbortRebase(projectPath string) error {
+	_, err := runGit(projectPath, "rebase", "--abort")
+	return err
+}
+
+// CherryPick applies the changes of the given commits on top of the current branch
+func (b *cliBackend) CherryPick(projectPath string, commits []string, noCommit bool) error {
+	args := []string{"cherry-pick"}
+	if noCommit {
+		args = append(args, "--no-commit")
+	}
+	_, err := runGit(projectPath, append(args, commits...)...)
+	return err
+}
+
+// Revert creates commits reverting the changes of the given commits
+func (b *cliBackend) Revert(projectPath string, commits []string, noCommit bool) error {
+	args := []string{"revert", "--no-edit"}
+	if noCommit {
+		args = append(args, "--no-commit")
+	}
+	_, err := runGit(projectPath, append(args, commits...)...)
+	return err
+}
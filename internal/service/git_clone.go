@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventCloneProgress is emitted while a repository is cloned
+const EventCloneProgress = "git:clone-progress"
+
+// CloneOptions describes a repository to clone
+type CloneOptions struct {
+	URL               string `json:"url"`               // Remote URL, file:// URLs and local paths included
+	Destination       string `json:"destination"`       // Directory to clone into, which must not exist or be empty
+	Branch            string `json:"branch"`            // Branch or tag to check out instead of the remote HEAD
+	Depth             int    `json:"depth"`             // Number of commits to fetch, the whole history when 0
+	RecurseSubmodules bool   `json:"recurseSubmodules"` // Clone the submodules too
+}
+
+// CloneProgress is the payload of a clone progress event
+type CloneProgress struct {
+	ID      string `json:"id"`      // Identifier given to Clone
+	Phase   string `json:"phase"`   // Phase reported by git, like "Receiving objects", empty for other messages
+	Percent int    `json:"percent"` // Completion of the phase
+	Current int    `json:"current"` // Objects or files done in the phase
+	Total   int    `json:"total"`   // Objects or files of the phase
+	Message string `json:"message"` // Line printed by git
+}
+
+// progressPattern matches git progress lines like "Receiving objects:  45% (450/1000), 1.20 MiB | 1.00 MiB/s"
+var progressPattern = regexp.MustCompile(`^(?:remote: )?([A-Za-z ]+):\s+(\d+)% \((\d+)/(\d+)\)`)
+
+// Clone clones a repository, emitting progress events with the given identifier,
+// and returns the absolute path of the clone. A clone in progress is stopped with
+// CancelClone, and a failed or cancelled clone leaves no files behind.
+func (s *GitService) Clone(id string, opts CloneOptions) (string, error) {
+	if opts.URL == "" {
+		return "", errors.New("no repository URL")
+	}
+	if opts.Destination == "" {
+		return "", errors.New("no destination")
+	}
+	destination, err := filepath.Abs(opts.Destination)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	// git refuses non-empty directories, checking first keeps their files safe from the cleanup
+	entries, err := os.ReadDir(destination)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read destination: %w", err)
+	}
+	if len(entries) > 0 {
+		return "", fmt.Errorf("destination %s is not empty", destination)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.clonesLock.Lock()
+	if _, running := s.clones[id]; running {
+		s.clonesLock.Unlock()
+		cancel()
+		return "", fmt.Errorf("clone %s is already running", id)
+	}
+	s.clones[id] = cancel
+	s.clonesLock.Unlock()
+	defer func() {
+		s.clonesLock.Lock()
+		delete(s.clones, id)
+		s.clonesLock.Unlock()
+		cancel()
+	}()
+
+	args := []string{"clone", "--progress"}
+	if opts.Branch != "" {
+		args = append(args, "--branch", opts.Branch)
+	}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if opts.RecurseSubmodules {
+		args = append(args, "--recurse-submodules")
+		if opts.Depth > 0 {
+			args = append(args, "--shallow-submodules")
+		}
+	}
+	args = append(args, "--", opts.URL, destination)
+
+	if err := s.runClone(ctx, id, args); err != nil {
+		// Remove what was cloned so far, git cannot clean up when it is killed
+		if existed {
+			removed, _ := os.ReadDir(destination)
+			for _, entry := range removed {
+				os.RemoveAll(filepath.Join(destination, entry.Name()))
+			}
+		} else {
+			os.RemoveAll(destination)
+		}
+
+		if ctx.Err() != nil {
+			return "", errors.New("clone cancelled")
+		}
+		return "", err
+	}
+
+	return destination, nil
+}
+
+// CancelClone stops a running clone
+func (s *GitService) CancelClone(id string) error {
+	s.clonesLock.Lock()
+	cancel, ok := s.clones[id]
+	s.clonesLock.Unlock()
+	if !ok {
+		return fmt.Errorf("no clone %s in progress", id)
+	}
+	cancel()
+	return nil
+}
+
+// cloneWaitDelay is how long a cancelled clone may take to release its output
+const cloneWaitDelay = 5 * time.Second
+
+// runClone runs "git clone" and turns its output into progress events
+func (s *GitService) runClone(ctx context.Context, id string, args []string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// Prompts cannot be answered, and progress must be parseable
+	cmd.Env = append(os.Environ(), "LC_ALL=C", "GIT_TERMINAL_PROMPT=0")
+	// Cancelling kills the helpers git spawns too, such as git-remote-https and
+	// index-pack, so none of them keeps writing to the destination
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd)
+	}
+	cmd.WaitDelay = cloneWaitDelay
+
+	progress := &cloneProgressWriter{s: s, id: id}
+	cmd.Stderr = progress
+	if err := cmd.Run(); err != nil {
+		progress.Write([]byte("\n"))
+
+		// Keep the errors, without the messages of what went well
+		var errorLines []string
+		for _, message := range progress.messages {
+			if strings.HasPrefix(message, "fatal:") || strings.HasPrefix(message, "error:") {
+				errorLines = append(errorLines, message)
+			}
+		}
+		if len(errorLines) == 0 {
+			errorLines = progress.messages
+		}
+		msg := strings.Join(errorLines, "\n")
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("git clone failed: %s", msg)
+	}
+	return nil
+}
+
+// cloneProgressWriter receives the output of "git clone" and emits its progress lines
+// as events. Progress lines are redrawn with carriage returns, messages end with newlines.
+type cloneProgressWriter struct {
+	s        *GitService
+	id       string
+	partial  []byte        // Start of a line not terminated yet
+	last     CloneProgress // Last progress sent
+	messages []string      // Lines that are not progress
+}
+
+// Write emits the complete lines of the output
+func (w *cloneProgressWriter) Write(data []byte) (int, error) {
+	w.partial = append(w.partial, data...)
+	for {
+		i := bytes.IndexAny(w.partial, "\r\n")
+		if i < 0 {
+			break
+		}
+		w.line(strings.TrimSpace(string(w.partial[:i])))
+		w.partial = w.partial[i+1:]
+	}
+	return len(data), nil
+}
+
+// line emits a line of output, progress lines only when their percentage changes
+func (w *cloneProgressWriter) line(line string) {
+	if line == "" {
+		return
+	}
+
+	progress := CloneProgress{ID: w.id, Message: line}
+	if match := progressPattern.FindStringSubmatch(line); match != nil {
+		progress.Phase = match[1]
+		progress.Percent, _ = strconv.Atoi(match[2])
+		progress.Current, _ = strconv.Atoi(match[3])
+		progress.Total, _ = strconv.Atoi(match[4])
+		if progress.Phase == w.last.Phase && progress.Percent == w.last.Percent && progress.Total == w.last.Total {
+			return
+		}
+	} else {
+		w.messages = append(w.messages, line)
+	}
+	w.last = progress
+	w.s.emit(EventCloneProgress, progress)
+}
@@ -0,0 +1,427 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// trailerPattern matches a "Key: value" commit message trailer line
+var trailerPattern = regexp.MustCompile(`^[A-Za-z0-9-]+: \S`)
+
+// CommitIdentity is the name and email of a commit author or committer
+type CommitIdentity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CommitOptions contains options for creating a commit
+type CommitOptions struct {
+	Amend      bool            `json:"amend"`      // Replace the HEAD commit, keeping its message when none is given
+	All        bool            `json:"all"`        // Stage modified and deleted tracked files first
+	AllowEmpty bool            `json:"allowEmpty"` // Allow a commit that records no changes
+	SignOff    bool            `json:"signOff"`    // Add a Signed-off-by trailer for the committer
+	Sign       bool            `json:"sign"`       // Sign with the configured OpenPGP key, always done when signing is enabled
+	Passphrase string          `json:"passphrase"` // Passphrase of an encrypted signing key
+	Author     *CommitIdentity `json:"author"`     // Overrides the configured author
+	Committer  *CommitIdentity `json:"committer"`  // Overrides the configured committer
+	SkipHooks  bool            `json:"skipHooks"`  // Skip the pre-commit and commit-msg hooks
+}
+
+// CommitResult describes a created commit
+type CommitResult struct {
+	Hash   string               `json:"hash"`
+	Issues []CommitMessageIssue `json:"issues"` // Convention problems of the message when the convention only warns
+}
+
+// Commit creates a new commit with the staged changes, running the repository's
+// commit hooks. Commits that record no changes are refused unless opts.AllowEmpty is set.
+func (s *GitService) Commit(projectPath string, message string, opts CommitOptions) (*CommitResult, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	before, err := currentRefState(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	commitOpts := &git.CommitOptions{AllowEmptyCommits: opts.AllowEmpty}
+
+	// prepare-commit-msg is told where the message comes from
+	messageSource := []string{"message"}
+
+	var amended *object.Commit
+	if opts.Amend {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HEAD: %w", err)
+		}
+		if amended, err = repo.CommitObject(head.Hash()); err != nil {
+			return nil, fmt.Errorf("failed to get commit: %w", err)
+		}
+
+		// go-git only keeps the first parent when amending, merge commits keep all of them
+		if amended.NumParents() > 1 {
+			commitOpts.Parents = amended.ParentHashes
+		} else {
+			commitOpts.Amend = true
+		}
+
+		if strings.TrimSpace(message) == "" {
+			message = amended.Message
+			messageSource = []string{"commit", amended.Hash.String()}
+		}
+	}
+
+	author, committer, err := commitIdentities(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	if amended != nil && opts.Author == nil {
+		// Like git, amending keeps the original author
+		author = &amended.Author
+	}
+	commitOpts.Author = author
+	commitOpts.Committer = committer
+
+	if opts.Sign || s.config.Signing.Enabled {
+		if commitOpts.SignKey, err = s.signingKey(opts.Passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.All {
+		// go-git cannot combine its All option with amending, so tracked changes are staged here
+		if err := s.stageTracked(projectPath, worktree); err != nil {
+			return nil, err
+		}
+	}
+
+	// Like git with an editor, comment lines of a given message, as left by a commit
+	// template, are removed. A message kept from the amended commit is used as is.
+	commentPrefix := ""
+	if messageSource[0] == "message" {
+		if commentPrefix, err = commentString(repo); err != nil {
+			return nil, err
+		}
+	}
+	message = cleanupMessage(message, commentPrefix)
+	if opts.SignOff {
+		message = addTrailer(message, fmt.Sprintf("Signed-off-by: %s <%s>", committer.Name, committer.Email))
+	}
+
+	// A message breaking an enforced convention is refused before any hook runs, and
+	// checked again once the hooks had a chance to change it. Empty messages are left
+	// for prepare-commit-msg to fill.
+	convention, err := s.GetCommitConvention(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(message) != "" {
+		if _, err := convention.enforce(message); err != nil {
+			return nil, err
+		}
+	}
+
+	if message, err = s.runCommitHooks(repo, projectPath, message, messageSource, opts.SkipHooks); err != nil {
+		return nil, err
+	}
+	message = cleanupMessage(message, commentPrefix)
+	if strings.TrimSpace(message) == "" {
+		return nil, errors.New("commit message is empty")
+	}
+
+	issues, err := convention.enforce(message)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := worktree.Commit(message+"\n", commitOpts)
+	if err != nil {
+		if errors.Is(err, git.ErrEmptyCommit) {
+			return nil, errors.New("nothing to commit, the commit would record no changes")
+		}
+		return nil, fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	if commit, err := repo.CommitObject(hash); err == nil {
+		if err := logCommit(repo, before, commit, amended != nil); err != nil {
+			log.Printf("[GitService] Failed to write reflog: %v", err)
+		}
+	}
+	s.recordUndo(projectPath, repo, "commit", RestoreSoft, before)
+
+	// Like git, a failing post-commit hook does not undo the commit
+	if err := s.runHook(repo, projectPath, HookPostCommit); err != nil {
+		log.Printf("[GitService] %v", err)
+	}
+
+	return &CommitResult{Hash: hash.String(), Issues: issues}, nil
+}
+
+// runCommitHooks runs the hooks that check and prepare a commit and returns the
+// message as left by them. Like git's --no-verify, skipHooks skips pre-commit
+// and commit-msg but not prepare-commit-msg.
+func (s *GitService) runCommitHooks(repo *git.Repository, projectPath string, message string, messageSource []string, skipHooks bool) (string, error) {
+	if !skipHooks {
+		if err := s.runHook(repo, projectPath, HookPreCommit); err != nil {
+			return "", err
+		}
+	}
+
+	// The message hooks edit the message file in place
+	dir, err := gitDir(repo)
+	if err != nil {
+		return "", err
+	}
+	messageFile := filepath.Join(dir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(messageFile, []byte(message+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write commit message: %w", err)
+	}
+
+	if err := s.runHook(repo, projectPath, HookPrepareCommitMsg, append([]string{messageFile}, messageSource...)...); err != nil {
+		return "", err
+	}
+	if !skipHooks {
+		if err := s.runHook(repo, projectPath, HookCommitMsg, messageFile); err != nil {
+			return "", err
+		}
+	}
+
+	content, err := os.ReadFile(messageFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit message: %w", err)
+	}
+
+	return strings.TrimRight(string(content), " \t\r\n"), nil
+}
+
+// cleanupMessage removes trailing whitespace, repeated blank lines and blank lines
+// at the start and end of a commit message, like "git stripspace". Lines starting
+// with the comment prefix are removed too, unless it is empty.
+func cleanupMessage(message string, commentPrefix string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(message, "\n") {
+		if commentPrefix != "" && strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commentString returns the prefix of comment lines in commit messages, set by
+// core.commentString or core.commentChar, "#" when unset or chosen automatically
+func commentString(repo *git.Repository) (string, error) {
+	cfg, err := repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	core := cfg.Raw.Section("core")
+	prefix := core.Option("commentString")
+	if prefix == "" {
+		prefix = core.Option("commentChar")
+	}
+	if prefix == "" || prefix == "auto" {
+		return "#", nil
+	}
+	return prefix, nil
+}
+
+// commitIdentities returns the author and committer of a new commit,
+// from the options or else from the repository configuration
+func commitIdentities(repo *git.Repository, opts CommitOptions) (*object.Signature, *object.Signature, error) {
+	now := time.Now()
+
+	var author, committer *object.Signature
+	if opts.Author != nil {
+		author = &object.Signature{Name: opts.Author.Name, Email: opts.Author.Email, When: now}
+	}
+	if opts.Committer != nil {
+		committer = &object.Signature{Name: opts.Committer.Name, Email: opts.Committer.Email, When: now}
+	}
+
+	if author == nil || committer == nil {
+		cfg, err := repo.ConfigScoped(config.SystemScope)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read git configuration: %w", err)
+		}
+
+		user := &object.Signature{Name: cfg.User.Name, Email: cfg.User.Email, When: now}
+		if author == nil {
+			author = user
+			if cfg.Author.Name != "" && cfg.Author.Email != "" {
+				author = &object.Signature{Name: cfg.Author.Name, Email: cfg.Author.Email, When: now}
+			}
+		}
+		if committer == nil {
+			committer = user
+			if cfg.Committer.Name != "" && cfg.Committer.Email != "" {
+				committer = &object.Signature{Name: cfg.Committer.Name, Email: cfg.Committer.Email, When: now}
+			}
+		}
+	}
+
+	for role, identity := range map[string]*object.Signature{"author": author, "committer": committer} {
+		if strings.TrimSpace(identity.Name) == "" || strings.TrimSpace(identity.Email) == "" {
+			return nil, nil, fmt.Errorf("no %s identity, set user.name and user.email in the git configuration", role)
+		}
+	}
+
+	return author, committer, nil
+}
+
+// addTrailer appends a trailer line to a commit message, joining the trailer
+// block at the end of the message when there is one. Existing identical trailers are kept as is.
+func addTrailer(message string, trailer string) string {
+	lines := strings.Split(message, "\n")
+	for _, line := range lines {
+		if strings.TrimSpace(line) == trailer {
+			return message
+		}
+	}
+
+	if trailerBlockStart(lines) >= 0 {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
+}
+
+// trailerBlockStart returns the index of the first line of the trailer block
+// ending the message lines, or -1 when the message has no trailers.
+// The subject line is never part of the trailer block.
+func trailerBlockStart(lines []string) int {
+	paragraphStart := 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			paragraphStart = i + 1
+		}
+	}
+	if paragraphStart == 0 || paragraphStart >= len(lines) {
+		return -1
+	}
+
+	for _, line := range lines[paragraphStart:] {
+		if !trailerPattern.MatchString(line) {
+			return -1
+		}
+	}
+	return paragraphStart
+}
+
+// stageTracked stages modified and deleted tracked files, leaving untracked files alone
+func (s *GitService) stageTracked(projectPath string, worktree *git.Worktree) error {
+	entries, err := s.status(projectPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Unmerged {
+			continue
+		}
+		switch entry.Worktree {
+		case StateModified, StateDeleted, StateTypeChanged:
+			if _, err := worktree.Add(entry.Path); err != nil {
+				return fmt.Errorf("failed to stage %s: %w", entry.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// signingKey loads the configured OpenPGP signing key, decrypting it when needed
+func (s *GitService) signingKey(passphrase string) (*openpgp.Entity, error) {
+	keyFile := s.config.Signing.KeyFile
+	if keyFile == "" {
+		return nil, errors.New("no signing key configured, set git.signing.keyFile in config.yaml")
+	}
+	if strings.HasPrefix(keyFile, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			keyFile = filepath.Join(homeDir, keyFile[2:])
+		}
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		if keyring, err = openpgp.ReadKeyRing(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("failed to parse signing key: %w", err)
+		}
+	}
+
+	keyID := strings.ToUpper(strings.TrimPrefix(s.config.Signing.KeyID, "0x"))
+	for _, entity := range keyring {
+		if entity.PrivateKey == nil || (keyID != "" && !entityMatches(entity, keyID)) {
+			continue
+		}
+
+		if entity.PrivateKey.Encrypted {
+			if passphrase == "" {
+				return nil, errors.New("the signing key is encrypted, a passphrase is required")
+			}
+			if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+			}
+		}
+
+		return entity, nil
+	}
+
+	if keyID != "" {
+		return nil, fmt.Errorf("no private key %s found in %s", s.config.Signing.KeyID, keyFile)
+	}
+	return nil, fmt.Errorf("no private key found in %s", keyFile)
+}
+
+// entityMatches reports whether the primary key or a subkey of an entity has the
+// given upper case key ID or fingerprint, or ends with it
+func entityMatches(entity *openpgp.Entity, keyID string) bool {
+	matches := func(fingerprint []byte) bool {
+		return strings.HasSuffix(strings.ToUpper(fmt.Sprintf("%X", fingerprint)), keyID)
+	}
+
+	if matches(entity.PrimaryKey.Fingerprint) {
+		return true
+	}
+	for _, subkey := range entity.Subkeys {
+		if matches(subkey.PublicKey.Fingerprint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/spf13/viper"
+)
+
+// Commit message convention modes
+const (
+	ConventionOff     = "off"     // Messages are not checked
+	ConventionWarn    = "warn"    // Problems are reported but the commit is created
+	ConventionEnforce = "enforce" // Commits with problems are refused
+)
+
+// commitConventionFile is the per-project convention file, relative to the project root
+var commitConventionFile = filepath.Join(".edit4i", "commit.yaml")
+
+// conventionalSubjectPattern matches a Conventional Commits subject: type(scope)!: description
+var conventionalSubjectPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\r\n]*)\))?(!)?: (\S.*)$`)
+
+// defaultCommitTypes are the Conventional Commits types allowed unless configured otherwise
+var defaultCommitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// CommitConvention describes the rules commit messages of a project must follow
+type CommitConvention struct {
+	Mode              string            `json:"mode" mapstructure:"mode"`                           // One of "off", "warn" or "enforce"
+	Conventional      bool              `json:"conventional" mapstructure:"conventional"`           // Require Conventional Commits subjects
+	Types             []string          `json:"types" mapstructure:"types"`                         // Allowed types
+	Scopes            []string          `json:"scopes" mapstructure:"scopes"`                       // Allowed scopes, empty allows any scope
+	RequireScope      bool              `json:"requireScope" mapstructure:"requireScope"`           // Every subject must have a scope
+	MaxSubjectLength  int               `json:"maxSubjectLength" mapstructure:"maxSubjectLength"`   // Maximum subject length, 0 for no limit
+	MaxBodyLineLength int               `json:"maxBodyLineLength" mapstructure:"maxBodyLineLength"` // Maximum body line length, 0 for no limit
+	RequiredTrailers  map[string]string `json:"requiredTrailers" mapstructure:"requiredTrailers"`   // Trailer keys that must be present, with an optional value pattern
+	Template          string            `json:"template" mapstructure:"template"`                   // Message template that pre-fills the message box
+}
+
+// CommitMessageIssue is a problem found in a commit message
+type CommitMessageIssue struct {
+	Rule    string `json:"rule"`    // Name of the rule that failed
+	Message string `json:"message"` // Human readable description
+	Line    int    `json:"line"`    // 1-based line of the message, 0 for the whole message
+}
+
+// GetCommitConvention returns the commit message convention of a project, read from
+// .edit4i/commit.yaml in the project. Without that file messages are not checked.
+func (s *GitService) GetCommitConvention(projectPath string) (*CommitConvention, error) {
+	convention := &CommitConvention{
+		Mode:  ConventionOff,
+		Types: defaultCommitTypes,
+	}
+
+	path := filepath.Join(projectPath, commitConventionFile)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return convention, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", commitConventionFile, err)
+	}
+
+	// Having a convention file turns checks on unless it says otherwise
+	convention.Mode = ConventionWarn
+	if err := v.Unmarshal(convention); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", commitConventionFile, err)
+	}
+
+	switch convention.Mode {
+	case ConventionOff, ConventionWarn, ConventionEnforce:
+	default:
+		return nil, fmt.Errorf("invalid mode %q in %s", convention.Mode, commitConventionFile)
+	}
+	for key, pattern := range convention.RequiredTrailers {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern for trailer %s in %s: %w", key, commitConventionFile, err)
+		}
+	}
+
+	return convention, nil
+}
+
+// ValidateCommitMessage checks a commit message against the convention of the project
+func (s *GitService) ValidateCommitMessage(projectPath string, message string) ([]CommitMessageIssue, error) {
+	convention, err := s.GetCommitConvention(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if convention.Mode == ConventionOff {
+		return []CommitMessageIssue{}, nil
+	}
+	return convention.check(message), nil
+}
+
+// GetCommitTemplate returns the message template of a project: the template of its
+// commit convention, or else the file set in git's commit.template
+func (s *GitService) GetCommitTemplate(projectPath string) (string, error) {
+	convention, err := s.GetCommitConvention(projectPath)
+	if err != nil {
+		return "", err
+	}
+	if convention.Template != "" {
+		return convention.Template, nil
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	cfg, err := repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	templatePath := cfg.Raw.Section("commit").Option("template")
+	switch {
+	case templatePath == "":
+		return "", nil
+	case strings.HasPrefix(templatePath, "~/"):
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		templatePath = filepath.Join(homeDir, templatePath[2:])
+	case !filepath.IsAbs(templatePath):
+		templatePath = filepath.Join(projectPath, templatePath)
+	}
+
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit template: %w", err)
+	}
+
+	return string(content), nil
+}
+
+// enforce returns the problems of a commit message under the convention, and an
+// error when the convention is enforced and the message has problems
+func (c *CommitConvention) enforce(message string) ([]CommitMessageIssue, error) {
+	if c.Mode == ConventionOff {
+		return []CommitMessageIssue{}, nil
+	}
+	issues := c.check(message)
+	if len(issues) > 0 && c.Mode == ConventionEnforce {
+		problems := make([]string, len(issues))
+		for i, issue := range issues {
+			problems[i] = issue.Message
+		}
+		return nil, fmt.Errorf("commit message does not follow the project convention: %s", strings.Join(problems, "; "))
+	}
+	return issues, nil
+}
+
+// check returns the problems of a commit message
+func (c *CommitConvention) check(message string) []CommitMessageIssue {
+	issues := []CommitMessageIssue{}
+	add := func(rule string, line int, format string, args ...interface{}) {
+		issues = append(issues, CommitMessageIssue{Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	lines := strings.Split(strings.TrimRight(message, " \t\r\n"), "\n")
+	subject := strings.TrimSpace(lines[0])
+	if subject == "" {
+		add("subject-empty", 1, "the subject line is empty")
+		return issues
+	}
+
+	if c.MaxSubjectLength > 0 && len([]rune(subject)) > c.MaxSubjectLength {
+		add("subject-length", 1, "the subject is %d characters long, the limit is %d", len([]rune(subject)), c.MaxSubjectLength)
+	}
+
+	// Messages generated by git itself keep their own format
+	generated := false
+	for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(subject, prefix) {
+			generated = true
+		}
+	}
+	if c.Conventional && !generated {
+		c.checkConventionalSubject(subject, add)
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		add("body-leading-blank", 2, "the subject must be followed by a blank line")
+	}
+
+	trailerStart := trailerBlockStart(lines)
+	bodyEnd := len(lines)
+	if trailerStart >= 0 {
+		bodyEnd = trailerStart
+	}
+	if c.MaxBodyLineLength > 0 {
+		for i := 1; i < bodyEnd; i++ {
+			if length := len([]rune(lines[i])); length > c.MaxBodyLineLength {
+				add("body-line-length", i+1, "line %d is %d characters long, the limit is %d", i+1, length, c.MaxBodyLineLength)
+			}
+		}
+	}
+
+	trailers := map[string][]string{}
+	if trailerStart >= 0 {
+		for _, line := range lines[trailerStart:] {
+			key, value, _ := strings.Cut(line, ":")
+			key = strings.ToLower(key)
+			trailers[key] = append(trailers[key], strings.TrimSpace(value))
+		}
+	}
+	keys := make([]string, 0, len(c.RequiredTrailers))
+	for key := range c.RequiredTrailers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pattern := c.RequiredTrailers[key]
+		values, ok := trailers[strings.ToLower(key)]
+		if !ok {
+			add("trailer-missing", 0, "the %s trailer is required", key)
+			continue
+		}
+		if pattern == "" {
+			continue
+		}
+		re := regexp.MustCompile(pattern)
+		matched := false
+		for _, value := range values {
+			if re.MatchString(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add("trailer-format", 0, "the %s trailer must match %s", key, pattern)
+		}
+	}
+
+	return issues
+}
+
+// checkConventionalSubject checks that the subject follows the Conventional Commits format
+func (c *CommitConvention) checkConventionalSubject(subject string, add func(rule string, line int, format string, args ...interface{})) {
+	match := conventionalSubjectPattern.FindStringSubmatch(subject)
+	if match == nil {
+		add("subject-format", 1, "the subject must look like \"type(scope): description\"")
+		return
+	}
+
+	commitType, scope := strings.ToLower(match[1]), match[2]
+	if len(c.Types) > 0 && !containsFold(c.Types, commitType) {
+		add("type-enum", 1, "type %q is not one of %s", commitType, strings.Join(c.Types, ", "))
+	}
+
+	switch {
+	case scope == "" && c.RequireScope:
+		add("scope-empty", 1, "a scope is required")
+	case scope != "" && len(c.Scopes) > 0 && !containsFold(c.Scopes, scope):
+		add("scope-enum", 1, "scope %q is not one of %s", scope, strings.Join(c.Scopes, ", "))
+	}
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
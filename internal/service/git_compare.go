@@ -0,0 +1,423 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/binary"
+)
+
+// Special revisions that can be used in comparisons besides branches, tags and commits
+const (
+	RevisionWorktree = ":worktree" // The files in the working directory
+	RevisionIndex    = ":index"    // The staging area
+)
+
+// CompareOptions describes the two sides of a comparison
+type CompareOptions struct {
+	Base      string `json:"base"`      // Base revision: branch, tag, hash, "HEAD~n", ":index" or ":worktree"
+	Target    string `json:"target"`    // Target revision, same forms as Base
+	MergeBase bool   `json:"mergeBase"` // Compare Target with the merge base of both sides ("three-dot" mode)
+}
+
+// CompareResult contains the files that differ between two revisions
+type CompareResult struct {
+	Base      string     `json:"base"`      // Resolved base commit hash, or the special revision
+	Target    string     `json:"target"`    // Resolved target commit hash, or the special revision
+	MergeBase string     `json:"mergeBase"` // Merge base used in three-dot mode
+	Files     []FileDiff `json:"files"`     // Changed files with status and stats; contents are loaded with GetCompareFileDiff
+	Stats     DiffStats  `json:"stats"`     // Totals over all changed files
+}
+
+// compareSide is one side of a comparison: either a commit tree or a set of
+// blob hashes read from the index or computed from the working directory
+type compareSide struct {
+	name   string
+	commit *object.Commit
+	files  map[string]plumbing.Hash
+	root   string // Set when the contents must be read from the working directory
+}
+
+// CompareRevisions returns the list of files changed between two revisions, with their stats
+func (s *GitService) CompareRevisions(projectPath string, opts CompareOptions) (*CompareResult, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	// Only the file list is returned here, patches are loaded on demand
+	result, diffs, err := s.compare(repo, opts, diffSelection{statsOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Files = diffs
+	if result.Files == nil {
+		result.Files = []FileDiff{}
+	}
+	for _, d := range diffs {
+		result.Stats.Added += d.Stats.Added
+		result.Stats.Deleted += d.Stats.Deleted
+		result.Stats.Modified += d.Stats.Modified
+	}
+
+	return result, nil
+}
+
+// GetCompareFileDiff returns the diff of a single file between two revisions
+func (s *GitService) GetCompareFileDiff(projectPath string, opts CompareOptions, filePath string) (*FileDiff, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	_, diffs, err := s.compare(repo, opts, diffSelection{path: filePath})
+	if err != nil {
+		return nil, err
+	}
+	if len(diffs) > 0 {
+		return &diffs[0], nil
+	}
+
+	return nil, fmt.Errorf("file %s has no changes between %s and %s", filePath, opts.Base, opts.Target)
+}
+
+// compare resolves both sides of a comparison and returns the selected per-file diffs between them
+func (s *GitService) compare(repo *git.Repository, opts CompareOptions, sel diffSelection) (*CompareResult, []FileDiff, error) {
+	base, err := s.resolveCompareSide(repo, opts.Base)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := s.resolveCompareSide(repo, opts.Target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &CompareResult{
+		Base:   base.name,
+		Target: target.name,
+	}
+
+	if opts.MergeBase {
+		if base.commit == nil || target.commit == nil {
+			return nil, nil, errors.New("merge base comparison requires two commits")
+		}
+
+		bases, err := base.commit.MergeBase(target.commit)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compute merge base: %w", err)
+		}
+		if len(bases) == 0 {
+			return nil, nil, fmt.Errorf("%s and %s have no common ancestor", opts.Base, opts.Target)
+		}
+
+		base = &compareSide{name: bases[0].Hash.String(), commit: bases[0]}
+		result.MergeBase = base.name
+	}
+
+	// Two commits can be compared directly with rename detection on their trees
+	if base.commit != nil && target.commit != nil {
+		baseTree, err := base.commit.Tree()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get tree: %w", err)
+		}
+		targetTree, err := target.commit.Tree()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get tree: %w", err)
+		}
+
+		diffs, err := s.selectDiffTrees(repo, baseTree, targetTree, sel)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result, diffs, nil
+	}
+
+	diffs, err := s.diffSides(repo, base, target, sel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, diffs, nil
+}
+
+// resolveCompareSide resolves a revision to one side of a comparison
+func (s *GitService) resolveCompareSide(repo *git.Repository, revision string) (*compareSide, error) {
+	switch revision {
+	case RevisionIndex, RevisionWorktree:
+		idx, err := repo.Storer.Index()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get index: %w", err)
+		}
+
+		side := &compareSide{
+			name:  revision,
+			files: make(map[string]plumbing.Hash, len(idx.Entries)),
+		}
+		for _, entry := range idx.Entries {
+			// Submodules have no blob, and for conflicted files our version is used
+			if entry.Mode == filemode.Submodule || (entry.Stage != 0 && entry.Stage != index.OurMode) {
+				continue
+			}
+			side.files[entry.Name] = entry.Hash
+		}
+
+		if revision == RevisionIndex {
+			return side, nil
+		}
+
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get worktree: %w", err)
+		}
+		side.root = worktree.Filesystem.Root()
+
+		// Hash the tracked files whose stat data no longer matches the index,
+		// the others are known to be unchanged
+		for _, entry := range idx.Entries {
+			if _, ok := side.files[entry.Name]; !ok {
+				continue
+			}
+			fullPath := filepath.Join(side.root, entry.Name)
+			info, err := os.Lstat(fullPath)
+			if err != nil {
+				delete(side.files, entry.Name)
+				continue
+			}
+			if info.IsDir() {
+				continue
+			}
+			if info.ModTime().Equal(entry.ModifiedAt) && uint32(info.Size()) == entry.Size {
+				continue
+			}
+
+			content, err := readWorktreeFile(fullPath, info)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", entry.Name, err)
+			}
+			side.files[entry.Name] = plumbing.ComputeHash(plumbing.BlobObject, content)
+		}
+
+		return side, nil
+	}
+
+	commit, err := resolveCommit(repo, revision)
+	if err != nil {
+		return nil, err
+	}
+	return &compareSide{name: commit.Hash.String(), commit: commit}, nil
+}
+
+// diffSides compares two sides where at least one is the index or the working directory.
+// Renames are detected when a deleted and an added file have identical contents.
+func (s *GitService) diffSides(repo *git.Repository, base, target *compareSide, sel diffSelection) ([]FileDiff, error) {
+	baseFiles, err := base.blobs()
+	if err != nil {
+		return nil, err
+	}
+	targetFiles, err := target.blobs()
+	if err != nil {
+		return nil, err
+	}
+
+	var modified, added, deleted []string
+	for path, hash := range targetFiles {
+		baseHash, ok := baseFiles[path]
+		switch {
+		case !ok:
+			added = append(added, path)
+		case baseHash != hash:
+			modified = append(modified, path)
+		}
+	}
+	for path := range baseFiles {
+		if _, ok := targetFiles[path]; !ok {
+			deleted = append(deleted, path)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(deleted)
+
+	// Pair deleted and added files with the same content as renames
+	renamedFrom := make(map[string]string)
+	deletedByHash := make(map[plumbing.Hash][]string)
+	for _, path := range deleted {
+		deletedByHash[baseFiles[path]] = append(deletedByHash[baseFiles[path]], path)
+	}
+	for _, path := range added {
+		if candidates := deletedByHash[targetFiles[path]]; len(candidates) > 0 {
+			renamedFrom[path] = candidates[0]
+			deletedByHash[targetFiles[path]] = candidates[1:]
+		}
+	}
+	renamedTo := make(map[string]bool, len(renamedFrom))
+	for _, oldPath := range renamedFrom {
+		renamedTo[oldPath] = true
+	}
+
+	rules, err := loadAttributeRules(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []FileDiff
+	appendDiff := func(status, oldPath, newPath string) error {
+		if !sel.matches(oldPath, newPath) {
+			return nil
+		}
+		var oldContent, newContent []byte
+		if oldPath != "" {
+			if oldContent, err = base.contents(repo, oldPath); err != nil {
+				return err
+			}
+		}
+		if newPath != "" {
+			if newContent, err = target.contents(repo, newPath); err != nil {
+				return err
+			}
+		}
+
+		fileDiff, err := s.diffContents(rules, oldPath, newPath, oldContent, newContent, sel.statsOnly)
+		if err != nil {
+			return err
+		}
+		fileDiff.Status = status
+		if status == "R" {
+			fileDiff.OldPath = oldPath
+		}
+
+		diffs = append(diffs, *fileDiff)
+		return nil
+	}
+
+	for _, path := range modified {
+		if err := appendDiff("M", path, path); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range added {
+		if oldPath, ok := renamedFrom[path]; ok {
+			if err := appendDiff("R", oldPath, path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := appendDiff("A", "", path); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range deleted {
+		if renamedTo[path] {
+			continue
+		}
+		if err := appendDiff("D", path, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+
+	return diffs, nil
+}
+
+// blobs returns the blob hash of every file on this side of the comparison
+func (c *compareSide) blobs() (map[string]plumbing.Hash, error) {
+	if c.commit == nil {
+		return c.files, nil
+	}
+
+	tree, err := c.commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	files := make(map[string]plumbing.Hash)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files[f.Name] = f.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk tree: %w", err)
+	}
+
+	return files, nil
+}
+
+// contents returns the contents of a file on this side of the comparison
+func (c *compareSide) contents(repo *git.Repository, path string) ([]byte, error) {
+	if c.root != "" {
+		fullPath := filepath.Join(c.root, path)
+		info, err := os.Lstat(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return readWorktreeFile(fullPath, info)
+	}
+
+	var hash plumbing.Hash
+	if c.commit != nil {
+		tree, err := c.commit.Tree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tree: %w", err)
+		}
+		entry, err := tree.FindEntry(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find %s in tree: %w", path, err)
+		}
+		hash = entry.Hash
+	} else {
+		hash = c.files[path]
+	}
+
+	return readBlob(repo, hash)
+}
+
+// readBlob returns the contents of a blob object
+func readBlob(repo *git.Repository, hash plumbing.Hash) ([]byte, error) {
+	blob, err := repo.BlobObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob object: %w", err)
+	}
+
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob reader: %w", err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob content: %w", err)
+	}
+	return content, nil
+}
+
+// readWorktreeFile returns the contents of a working directory file the way git stores it,
+// using the link target for symbolic links
+func readWorktreeFile(fullPath string, info os.FileInfo) ([]byte, error) {
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(target), nil
+	}
+	return os.ReadFile(fullPath)
+}
+
+// isBinaryContent checks if content looks binary, using the same heuristic as git
+func isBinaryContent(content []byte) bool {
+	isBinary, err := binary.IsBinary(bytes.NewReader(content))
+	return err == nil && isBinary
+}
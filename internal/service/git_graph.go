@@ -0,0 +1,279 @@
+package service
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// GraphEdge is a line segment of the commit graph between two lanes
+type GraphEdge struct {
+	From int `json:"from"` // Lane at the start of the segment
+	To   int `json:"to"`   // Lane at the end of the segment
+}
+
+// GraphRow describes how to draw the commit graph for one commit row.
+// Incoming edges go from the top of the row to the middle, where the commit
+// node is drawn, and outgoing edges go from the middle to the bottom.
+type GraphRow struct {
+	Lane      int         `json:"lane"`      // Lane of the commit node
+	LaneCount int         `json:"laneCount"` // Number of lanes in use in this row
+	Incoming  []GraphEdge `json:"incoming"`  // Edges from the previous row to this one
+	Outgoing  []GraphEdge `json:"outgoing"`  // Edges from this row to the next one
+	IsMerge   bool        `json:"isMerge"`   // The commit has more than one parent
+	IsFork    bool        `json:"isFork"`    // More than one child lane ends at this commit
+	Branches  []string    `json:"branches"`  // Local and remote branches pointing at the commit
+	Tags      []string    `json:"tags"`      // Tags pointing at the commit
+	IsHead    bool        `json:"isHead"`    // HEAD points at the commit
+}
+
+// graphBuilder assigns commits to lanes while walking history from newest to oldest.
+// Because ListCommits always walks from the same starting point and feeds every
+// commit to the builder, including the ones skipped by pagination, the layout of
+// a page is the same as if the whole history had been laid out at once.
+type graphBuilder struct {
+	lanes    []plumbing.Hash // Commit expected next in each lane, zero for a free lane
+	branches map[plumbing.Hash][]string
+	tags     map[plumbing.Hash][]string
+	head     plumbing.Hash
+}
+
+// newGraphBuilder creates a graph builder with the ref labels of the repository
+func newGraphBuilder(repo *git.Repository, tags map[plumbing.Hash][]string) (*graphBuilder, error) {
+	b := &graphBuilder{
+		branches: make(map[plumbing.Hash][]string),
+		tags:     tags,
+	}
+
+	if head, err := repo.Head(); err == nil {
+		b.head = head.Hash()
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		if ref.Name().IsBranch() || ref.Name().IsRemote() {
+			b.branches[ref.Hash()] = append(b.branches[ref.Hash()], ref.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate references: %w", err)
+	}
+
+	for _, names := range b.branches {
+		sort.Strings(names)
+	}
+
+	return b, nil
+}
+
+// next lays out the next commit of the walk and returns its graph row
+func (b *graphBuilder) next(c *object.Commit) *GraphRow {
+	row := &GraphRow{
+		IsMerge:  c.NumParents() > 1,
+		Branches: b.branches[c.Hash],
+		Tags:     b.tags[c.Hash],
+		IsHead:   c.Hash == b.head,
+	}
+
+	// Find the lanes waiting for this commit; the first one holds the node
+	row.Lane = -1
+	children := 0
+	for i, hash := range b.lanes {
+		if hash == c.Hash {
+			if row.Lane < 0 {
+				row.Lane = i
+			}
+			children++
+		}
+	}
+	if row.Lane < 0 {
+		// Nothing leads to this commit, it starts a new lane
+		row.Lane = b.freeLane()
+	}
+	row.IsFork = children > 1
+
+	for i, hash := range b.lanes {
+		switch {
+		case hash == c.Hash:
+			row.Incoming = append(row.Incoming, GraphEdge{From: i, To: row.Lane})
+		case !hash.IsZero():
+			row.Incoming = append(row.Incoming, GraphEdge{From: i, To: i})
+		}
+	}
+
+	// Lanes that ended at this commit are freed, the node's lane continues with the first parent
+	for i, hash := range b.lanes {
+		if hash == c.Hash {
+			b.lanes[i] = plumbing.ZeroHash
+		}
+	}
+
+	targets := make(map[int]bool)
+	if c.NumParents() > 0 {
+		b.lanes[row.Lane] = c.ParentHashes[0]
+		targets[row.Lane] = true
+	}
+
+	// Other parents join a lane already waiting for them or open a new one
+	for _, parent := range c.ParentHashes[min(1, len(c.ParentHashes)):] {
+		lane := -1
+		for i, hash := range b.lanes {
+			if hash == parent {
+				lane = i
+				break
+			}
+		}
+		if lane < 0 {
+			lane = b.freeLane()
+			b.lanes[lane] = parent
+		}
+		targets[lane] = true
+	}
+
+	for i, hash := range b.lanes {
+		switch {
+		case targets[i]:
+			row.Outgoing = append(row.Outgoing, GraphEdge{From: row.Lane, To: i})
+		case !hash.IsZero():
+			row.Outgoing = append(row.Outgoing, GraphEdge{From: i, To: i})
+		}
+	}
+
+	// Drop free lanes at the end so the graph stays as narrow as possible
+	for len(b.lanes) > 0 && b.lanes[len(b.lanes)-1].IsZero() {
+		b.lanes = b.lanes[:len(b.lanes)-1]
+	}
+
+	row.LaneCount = max(len(b.lanes), row.Lane+1)
+	for _, edge := range row.Incoming {
+		row.LaneCount = max(row.LaneCount, edge.From+1)
+	}
+
+	return row
+}
+
+// freeLane returns the index of the first free lane, adding one if needed
+func (b *graphBuilder) freeLane() int {
+	for i, hash := range b.lanes {
+		if hash.IsZero() {
+			return i
+		}
+	}
+	b.lanes = append(b.lanes, plumbing.ZeroHash)
+	return len(b.lanes) - 1
+}
+
+// topoOrderLog returns the history of a commit newest first, never returning a
+// commit before all of its children, like "git log --date-order". The whole
+// history is read first, as git does, to know the children of every commit.
+func topoOrderLog(repo *git.Repository, from plumbing.Hash) (object.CommitIter, error) {
+	commits := make(map[plumbing.Hash]*object.Commit)
+	children := make(map[plumbing.Hash]int)
+
+	pending := []plumbing.Hash{from}
+	for len(pending) > 0 {
+		hash := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if _, seen := commits[hash]; seen {
+			continue
+		}
+
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			// Parents missing from shallow clones end the history
+			if errors.Is(err, plumbing.ErrObjectNotFound) && hash != from {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get commit %s: %w", hash, err)
+		}
+		commits[hash] = c
+		for _, parent := range c.ParentHashes {
+			children[parent]++
+			pending = append(pending, parent)
+		}
+	}
+
+	// Commits whose children were all returned are ready, the newest one goes first
+	ordered := make([]*object.Commit, 0, len(commits))
+	ready := &commitQueue{commits[from]}
+	for ready.Len() > 0 {
+		c := heap.Pop(ready).(*object.Commit)
+		ordered = append(ordered, c)
+		for _, parent := range c.ParentHashes {
+			children[parent]--
+			if p, ok := commits[parent]; ok && children[parent] == 0 {
+				heap.Push(ready, p)
+			}
+		}
+	}
+
+	return &commitSliceIter{commits: ordered}, nil
+}
+
+// commitQueue is a heap of commits, the latest committed first
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+func (q commitQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x interface{}) { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// commitSliceIter iterates over commits already in order
+type commitSliceIter struct {
+	commits []*object.Commit
+	pos     int
+}
+
+// Next returns the next commit, io.EOF at the end
+func (it *commitSliceIter) Next() (*object.Commit, error) {
+	if it.pos >= len(it.commits) {
+		return nil, io.EOF
+	}
+	c := it.commits[it.pos]
+	it.pos++
+	return c, nil
+}
+
+// ForEach calls the callback for each remaining commit, until it returns storer.ErrStop
+func (it *commitSliceIter) ForEach(cb func(*object.Commit) error) error {
+	for {
+		c, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err := cb(c); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Close ends the iteration
+func (it *commitSliceIter) Close() {
+	it.pos = len(it.commits)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// CommitDetails represents a commit together with the changes it introduced
+type CommitDetails struct {
+	Hash           string     `json:"hash"`
+	Message        string     `json:"message"` // Full commit message
+	Author         string     `json:"author"`
+	AuthorEmail    string     `json:"authorEmail"`
+	AuthorDate     time.Time  `json:"authorDate"`
+	Committer      string     `json:"committer"`
+	CommitterEmail string     `json:"committerEmail"`
+	CommitterDate  time.Time  `json:"committerDate"`
+	ParentHashes   []string   `json:"parentHashes"`
+	ComparedParent string     `json:"comparedParent"` // Parent the changes are computed against, empty for root commits
+	Files          []FileDiff `json:"files"`          // Changed files with their status, stats and patch
+	Stats          DiffStats  `json:"stats"`          // Totals over all changed files
+}
+
+// GetCommitDetails returns the full metadata of a commit and the files it changed.
+// Changes are computed against the parent at parentIndex (0 for the first parent),
+// which allows choosing the side of a merge commit to compare with.
+func (s *GitService) GetCommitDetails(projectPath string, hash string, parentIndex int) (*CommitDetails, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	commit, err := resolveCommit(repo, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	details := &CommitDetails{
+		Hash:           commit.Hash.String(),
+		Message:        commit.Message,
+		Author:         commit.Author.Name,
+		AuthorEmail:    commit.Author.Email,
+		AuthorDate:     commit.Author.When,
+		Committer:      commit.Committer.Name,
+		CommitterEmail: commit.Committer.Email,
+		CommitterDate:  commit.Committer.When,
+		ParentHashes:   make([]string, len(commit.ParentHashes)),
+	}
+	for i, parentHash := range commit.ParentHashes {
+		details.ParentHashes[i] = parentHash.String()
+	}
+
+	// Root commits are compared with an empty tree
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		if parentIndex < 0 || parentIndex >= commit.NumParents() {
+			return nil, fmt.Errorf("commit %s has no parent %d", commit.Hash, parentIndex)
+		}
+
+		parent, err := commit.Parent(parentIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent commit: %w", err)
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent tree: %w", err)
+		}
+		details.ComparedParent = parent.Hash.String()
+	}
+
+	details.Files, err = s.diffTrees(repo, parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range details.Files {
+		details.Stats.Added += file.Stats.Added
+		details.Stats.Deleted += file.Stats.Deleted
+		details.Stats.Modified += file.Stats.Modified
+	}
+
+	return details, nil
+}
+
+// resolveCommit resolves a revision (full or abbreviated hash, branch, tag or
+// expressions such as "HEAD~2") to a commit object
+func resolveCommit(repo *git.Repository, revision string) (*object.Commit, error) {
+	revision = strings.TrimSpace(revision)
+	if revision == "" {
+		return nil, fmt.Errorf("no revision specified")
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %q: %w", revision, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit object: %w", err)
+	}
+
+	return commit, nil
+}
+
+// GetFileAtRevision returns the content of a file as stored in a revision,
+// so old versions can be opened read-only
+func (s *GitService) GetFileAtRevision(projectPath string, revision string, filePath string) (string, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	commit, err := resolveCommit(repo, revision)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := commit.File(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s at %s: %w", filePath, revision, err)
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return "", fmt.Errorf("failed to get file contents: %w", err)
+	}
+
+	return content, nil
+}
+
+// pathTracker matches commits touching a file or directory while walking history,
+// optionally following a file across renames
+type pathTracker struct {
+	path   string
+	follow bool
+}
+
+// touches reports whether a commit changed the tracked path. A commit touches the path
+// when its version differs from every parent, so merges that took one side unchanged
+// are skipped. When following renames, a commit that renamed the file switches the
+// tracked path to the previous name for the older commits.
+func (t *pathTracker) touches(c *object.Commit) (bool, error) {
+	entry, err := pathEntry(c, t.path)
+	if err != nil {
+		return false, err
+	}
+
+	if c.NumParents() == 0 {
+		return entry != nil, nil
+	}
+
+	addedHere := true
+	for i := range c.ParentHashes {
+		parent, err := c.Parent(i)
+		if err != nil {
+			return false, fmt.Errorf("failed to get parent commit: %w", err)
+		}
+
+		parentEntry, err := pathEntry(parent, t.path)
+		if err != nil {
+			return false, err
+		}
+		if sameEntry(entry, parentEntry) {
+			return false, nil
+		}
+		if parentEntry != nil {
+			addedHere = false
+		}
+	}
+
+	if t.follow && entry != nil && addedHere && entry.Mode.IsFile() {
+		previous, err := renameSource(c, t.path)
+		if err != nil {
+			return false, err
+		}
+		if previous != "" {
+			t.path = previous
+		}
+	}
+
+	return true, nil
+}
+
+// pathEntry returns the tree entry for a path in a commit, or nil if it does not exist
+func pathEntry(c *object.Commit, path string) (*object.TreeEntry, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	entry, err := tree.FindEntry(path)
+	if err != nil {
+		if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find %s in tree: %w", path, err)
+	}
+
+	return entry, nil
+}
+
+// sameEntry reports whether two optional tree entries have the same content and mode
+func sameEntry(a, b *object.TreeEntry) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Hash == b.Hash && a.Mode == b.Mode
+}
+
+// renameSource returns the previous path of a file that a commit renamed to path,
+// or an empty string if the file was not renamed
+func renameSource(c *object.Commit, path string) (string, error) {
+	parent, err := c.Parent(0)
+	if err != nil {
+		return "", fmt.Errorf("failed to get parent commit: %w", err)
+	}
+
+	parentTree, err := parent.Tree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get parent tree: %w", err)
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	changes, err := object.DiffTreeWithOptions(context.Background(), parentTree, tree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff trees: %w", err)
+	}
+
+	for _, change := range changes {
+		if change.To.Name == path && change.From.Name != "" && change.From.Name != path {
+			return change.From.Name, nil
+		}
+	}
+
+	return "", nil
+}
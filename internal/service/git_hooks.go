@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// EventHookOutput is emitted for every line a git hook prints
+const EventHookOutput = "git:hook-output"
+
+// Commit hooks, in the order git runs them
+const (
+	HookPreCommit        = "pre-commit"
+	HookPrepareCommitMsg = "prepare-commit-msg"
+	HookCommitMsg        = "commit-msg"
+	HookPostCommit       = "post-commit"
+)
+
+// HookOutput is the payload of a hook output event
+type HookOutput struct {
+	ProjectPath string `json:"projectPath"`
+	Hook        string `json:"hook"` // Name of the running hook
+	Line        string `json:"line"` // Line printed by the hook on stdout or stderr
+}
+
+// runHook runs a hook of the repository if it exists and is executable. The hook
+// output is streamed as events and included in the returned error when it fails.
+func (s *GitService) runHook(repo *git.Repository, projectPath string, name string, args ...string) error {
+	hookPath, err := hookPath(repo, name)
+	if err != nil {
+		return err
+	}
+	if hookPath == "" {
+		return nil
+	}
+
+	dir, err := gitDir(repo)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(hookPath, args...)
+	if runtime.GOOS == "windows" {
+		// Hooks are shell scripts, Git for Windows runs them with its bundled sh
+		cmd = exec.Command("sh", append([]string{hookPath}, args...)...)
+	}
+	cmd.Dir = projectPath
+	cmd.Env = append(os.Environ(),
+		"GIT_INDEX_FILE="+filepath.Join(dir, "index"),
+		// Hooks must not wait for an editor, the message was already written
+		"GIT_EDITOR=:",
+	)
+
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	var output strings.Builder
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			line := scanner.Text()
+			output.WriteString(line + "\n")
+			s.emit(EventHookOutput, HookOutput{ProjectPath: projectPath, Hook: name, Line: line})
+		}
+		// Keep draining so the hook never blocks on a full pipe
+		io.Copy(io.Discard, reader)
+	}()
+
+	runErr := cmd.Run()
+	writer.Close()
+	wg.Wait()
+
+	if runErr != nil {
+		msg := strings.TrimSpace(output.String())
+		if msg == "" {
+			msg = runErr.Error()
+		}
+		return fmt.Errorf("%s hook failed: %s", name, msg)
+	}
+
+	return nil
+}
+
+// hookPath returns the path of an executable hook, or an empty string when the
+// repository does not have it. core.hooksPath is honoured like git does.
+func hookPath(repo *git.Repository, name string) (string, error) {
+	cfg, err := repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	hooksDir := cfg.Raw.Section("core").Option("hooksPath")
+	switch {
+	case hooksDir == "":
+		dir, err := gitDir(repo)
+		if err != nil {
+			return "", err
+		}
+		// Linked worktrees share the hooks of the main repository
+		hooksDir = filepath.Join(commonGitDir(dir), "hooks")
+	case strings.HasPrefix(hooksDir, "~/"):
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		hooksDir = filepath.Join(homeDir, hooksDir[2:])
+	case !filepath.IsAbs(hooksDir):
+		// Relative hook paths are relative to the root of the working tree
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return "", fmt.Errorf("failed to get worktree: %w", err)
+		}
+		hooksDir = filepath.Join(worktree.Filesystem.Root(), hooksDir)
+	}
+
+	path := filepath.Join(hooksDir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to check %s hook: %w", name, err)
+	}
+	if info.IsDir() || (runtime.GOOS != "windows" && info.Mode()&0111 == 0) {
+		// git ignores hooks that are not executable
+		return "", nil
+	}
+
+	return path, nil
+}
+
+// gitDir returns the path of the repository's git directory
+func gitDir(repo *git.Repository) (string, error) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", errors.New("repository is not stored on disk")
+	}
+	return storage.Filesystem().Root(), nil
+}
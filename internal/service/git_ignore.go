@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// Ignore files rules can be added to
+const (
+	IgnoreFileProject = "gitignore" // .gitignore at the root of the working tree, shared with the repository
+	IgnoreFileExclude = "exclude"   // info/exclude of the repository, for this clone only
+	IgnoreFileGlobal  = "global"    // The global excludes file, for every repository of the user
+)
+
+// IgnoreCheck tells whether a path is ignored and which rule decides it
+type IgnoreCheck struct {
+	Path    string `json:"path"`    // Path as given
+	Ignored bool   `json:"ignored"` // Whether git ignores the path
+	Tracked bool   `json:"tracked"` // Tracked files, and directories containing some, are not ignored whatever the rules
+	Source  string `json:"source"`  // File of the matching rule, empty when no rule matches
+	Line    int    `json:"line"`    // Line of the rule in its file
+	Pattern string `json:"pattern"` // The rule as written, a negated rule when it re-includes the path
+}
+
+// CheckIgnore tells for each path, relative to the project or absolute, whether it is
+// ignored and which rule of which ignore file decides it
+func (s *GitService) CheckIgnore(projectPath string, paths []string) ([]IgnoreCheck, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	matcher, err := repoIgnoreMatcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+	tracked := trackedDirs(idx)
+	for _, entry := range idx.Entries {
+		tracked[entry.Name] = true
+	}
+
+	checks := make([]IgnoreCheck, 0, len(paths))
+	for _, path := range paths {
+		rel, isDir, err := ignoreRelPath(matcher.root, projectPath, path)
+		if err != nil {
+			return nil, err
+		}
+
+		check := IgnoreCheck{Path: path, Tracked: tracked[rel]}
+		rule, excluded, err := matcher.match(rel, isDir)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			check.Source = rule.file
+			check.Line = rule.line
+			check.Pattern = rule.text
+		}
+		check.Ignored = excluded && !check.Tracked
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// AddIgnorePattern appends a pattern to an ignore file, one of the IgnoreFile
+// constants, unless the file already has it, and returns the path of the file
+func (s *GitService) AddIgnorePattern(projectPath string, pattern string, file string) (string, error) {
+	if strings.TrimSpace(pattern) == "" || strings.ContainsAny(pattern, "\r\n") {
+		return "", fmt.Errorf("invalid ignore pattern: %q", pattern)
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	ignoreFile, err := ignoreFilePath(repo, file)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(ignoreFile)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", ignoreFile, err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSuffix(line, "\r") == pattern {
+			return ignoreFile, nil
+		}
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		pattern = "\n" + pattern
+	}
+	if err := os.MkdirAll(filepath.Dir(ignoreFile), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.OpenFile(ignoreFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", ignoreFile, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(pattern + "\n"); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", ignoreFile, err)
+	}
+
+	s.invalidateStatus(projectPath)
+	return ignoreFile, nil
+}
+
+// IgnorePath adds a rule ignoring exactly one file or directory, relative to the
+// project or absolute, to an ignore file and returns the path of the file
+func (s *GitService) IgnorePath(projectPath string, path string, file string) (string, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+	rel, isDir, err := ignoreRelPath(worktree.Filesystem.Root(), projectPath, path)
+	if err != nil {
+		return "", err
+	}
+
+	// Anchored to the root, with the characters patterns give a meaning escaped
+	var pattern strings.Builder
+	pattern.WriteString("/")
+	for _, r := range rel {
+		if strings.ContainsRune(`*?[\`, r) {
+			pattern.WriteByte('\\')
+		}
+		pattern.WriteRune(r)
+	}
+	escaped := pattern.String()
+	if strings.HasSuffix(escaped, " ") {
+		// Trailing spaces are dropped unless the last one is escaped
+		escaped = escaped[:len(escaped)-1] + `\ `
+	}
+	if isDir {
+		escaped += "/"
+	}
+
+	return s.AddIgnorePattern(projectPath, escaped, file)
+}
+
+// ignoreRelPath returns a path given relative to the project, or absolute, relative to
+// the root of the working tree, and whether it is a directory
+func ignoreRelPath(root string, projectPath string, path string) (string, bool, error) {
+	isDir := strings.HasSuffix(path, "/")
+	fullPath := path
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(projectPath, path)
+	}
+	if info, err := os.Lstat(fullPath); err == nil {
+		isDir = info.IsDir()
+	}
+
+	rel, err := filepath.Rel(root, fullPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false, fmt.Errorf("path %s is not in the working tree", path)
+	}
+	return filepath.ToSlash(rel), isDir, nil
+}
+
+// ignoreFilePath returns the path of one of the ignore files rules are added to
+func ignoreFilePath(repo *git.Repository, file string) (string, error) {
+	switch file {
+	case IgnoreFileProject:
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return "", fmt.Errorf("failed to get worktree: %w", err)
+		}
+		return filepath.Join(worktree.Filesystem.Root(), ".gitignore"), nil
+	case IgnoreFileExclude:
+		dir, err := gitDir(repo)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(commonGitDir(dir), "info", "exclude"), nil
+	case IgnoreFileGlobal:
+		path, err := globalExcludesFile(repo)
+		if err != nil {
+			return "", err
+		}
+		if path == "" {
+			return "", errors.New("no global excludes file")
+		}
+		return path, nil
+	default:
+		return "", fmt.Errorf("unknown ignore file: %q", file)
+	}
+}
@@ -0,0 +1,472 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+// StatusConflicted is the FileStatus code of files with unresolved merge conflicts
+const StatusConflicted = "U"
+
+// Merge result states
+const (
+	MergeUpToDate    = "up-to-date"
+	MergeFastForward = "fast-forward"
+	MergeMerged      = "merged"
+	MergeConflicts   = "conflicts"
+)
+
+// Conflict resolution strategies
+const (
+	ResolveOurs    = "ours"    // Keep the version of the current branch
+	ResolveTheirs  = "theirs"  // Keep the version of the merged branch
+	ResolveBoth    = "both"    // Keep both sides of every conflict region, ours first
+	ResolveContent = "content" // Use the supplied merged content
+)
+
+// MergeOptions contains options for merging a branch
+type MergeOptions struct {
+	NoFastForward   bool   `json:"noFastForward"`   // Always create a merge commit
+	FastForwardOnly bool   `json:"fastForwardOnly"` // Refuse to merge unless it can be fast-forwarded
+	Message         string `json:"message"`         // Message of the merge commit, defaults to git's message
+}
+
+// MergeResult describes the outcome of a merge
+type MergeResult struct {
+	Status    string   `json:"status"`    // One of "up-to-date", "fast-forward", "merged" or "conflicts"
+	Commit    string   `json:"commit"`    // HEAD after the merge
+	Conflicts []string `json:"conflicts"` // Conflicted files when the merge stopped
+}
+
+// MergeState describes a merge in progress
+type MergeState struct {
+	InProgress bool     `json:"inProgress"`
+	MergeHead  string   `json:"mergeHead"` // Commit being merged
+	Message    string   `json:"message"`   // Prepared merge commit message
+	Conflicts  []string `json:"conflicts"` // Files that still have conflicts
+}
+
+// ConflictRegion is a block of conflict markers in a conflicted file
+type ConflictRegion struct {
+	StartLine   int    `json:"startLine"`   // 1-based line of the "<<<<<<<" marker
+	EndLine     int    `json:"endLine"`     // 1-based line of the ">>>>>>>" marker
+	Ours        string `json:"ours"`        // Lines of the current branch
+	Base        string `json:"base"`        // Lines of the common ancestor, only with diff3 style markers
+	Theirs      string `json:"theirs"`      // Lines of the merged branch
+	OursLabel   string `json:"oursLabel"`   // Label after the "<<<<<<<" marker
+	TheirsLabel string `json:"theirsLabel"` // Label after the ">>>>>>>" marker
+}
+
+// ConflictInfo contains the versions of a conflicted file
+type ConflictInfo struct {
+	Path      string           `json:"path"`
+	Base      string           `json:"base"`      // Common ancestor version
+	Ours      string           `json:"ours"`      // Current branch version
+	Theirs    string           `json:"theirs"`    // Merged branch version
+	HasBase   bool             `json:"hasBase"`   // False when both sides added the file
+	HasOurs   bool             `json:"hasOurs"`   // False when the current branch deleted the file
+	HasTheirs bool             `json:"hasTheirs"` // False when the merged branch deleted the file
+	IsBinary  bool             `json:"isBinary"`
+	Working   string           `json:"working"` // Working copy with conflict markers
+	Regions   []ConflictRegion `json:"regions"` // Conflict regions parsed from the working copy
+}
+
+// ConflictResolution describes how to resolve a conflicted file
+type ConflictResolution struct {
+	Strategy string `json:"strategy"` // One of "ours", "theirs", "both" or "content"
+	Content  string `json:"content"`  // Merged content for the "content" strategy
+}
+
+// MergeBranch merges a branch into the current one. Conflicts are not an error:
+// the merge stops and the result lists the conflicted files.
+func (s *GitService) MergeBranch(projectPath string, branch string, opts MergeOptions) (*MergeResult, error) {
+	if branch == "" || strings.HasPrefix(branch, "-") {
+		return nil, fmt.Errorf("invalid branch name: %q", branch)
+	}
+	if opts.NoFastForward && opts.FastForwardOnly {
+		return nil, errors.New("no-fast-forward and fast-forward-only cannot be combined")
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	target, err := repo.ResolveRevision(plumbing.Revision(branch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", branch, err)
+	}
+
+	before, err := currentRefState(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// go-git can only fast-forward, three-way merges go through the git CLI
+	args := []string{"merge", "--no-edit"}
+	switch {
+	case opts.NoFastForward:
+		args = append(args, "--no-ff")
+	case opts.FastForwardOnly:
+		args = append(args, "--ff-only")
+	}
+	if opts.Message != "" {
+		args = append(args, "-m", opts.Message)
+	}
+	args = append(args, branch)
+
+	_, mergeErr := runGit(projectPath, args...)
+
+	conflicts, err := conflictedFiles(repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return &MergeResult{
+			Status:    MergeConflicts,
+			Commit:    head.Hash().String(),
+			Conflicts: conflicts,
+		}, nil
+	}
+	if mergeErr != nil {
+		return nil, fmt.Errorf("failed to merge %s: %w", branch, mergeErr)
+	}
+
+	s.recordUndo(projectPath, repo, "merge", RestoreKeep, before)
+
+	newHead, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	result := &MergeResult{
+		Status:    MergeMerged,
+		Commit:    newHead.Hash().String(),
+		Conflicts: []string{},
+	}
+	switch newHead.Hash() {
+	case head.Hash():
+		result.Status = MergeUpToDate
+	case *target:
+		result.Status = MergeFastForward
+	}
+
+	return result, nil
+}
+
+// AbortMerge aborts the merge in progress and restores the state from before it started
+func (s *GitService) AbortMerge(projectPath string) error {
+	if _, err := runGit(projectPath, "merge", "--abort"); err != nil {
+		return fmt.Errorf("failed to abort merge: %w", err)
+	}
+	return nil
+}
+
+// GetMergeState returns whether a merge is in progress and its remaining conflicts
+func (s *GitService) GetMergeState(projectPath string) (*MergeState, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	state := &MergeState{}
+
+	mergeHead, err := readGitDirFile(repo, "MERGE_HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if mergeHead != "" {
+		state.InProgress = true
+		state.MergeHead = strings.TrimSpace(strings.SplitN(mergeHead, "\n", 2)[0])
+		if state.Message, err = readGitDirFile(repo, "MERGE_MSG"); err != nil {
+			return nil, err
+		}
+	}
+
+	if state.Conflicts, err = conflictedFiles(repo); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// GetConflict returns the base, ours and theirs versions of a conflicted file
+// together with the conflict regions found in its working copy
+func (s *GitService) GetConflict(projectPath string, file string) (*ConflictInfo, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	stages, err := conflictStages(repo, file)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ConflictInfo{Path: file}
+	for stage, target := range map[index.Stage]*string{
+		index.AncestorMode: &info.Base,
+		index.OurMode:      &info.Ours,
+		index.TheirMode:    &info.Theirs,
+	} {
+		hash, ok := stages[stage]
+		if !ok {
+			continue
+		}
+		content, err := readBlob(repo, hash)
+		if err != nil {
+			return nil, err
+		}
+		if isBinaryContent(content) {
+			info.IsBinary = true
+		}
+		*target = string(content)
+	}
+	_, info.HasBase = stages[index.AncestorMode]
+	_, info.HasOurs = stages[index.OurMode]
+	_, info.HasTheirs = stages[index.TheirMode]
+
+	info.Regions = []ConflictRegion{}
+	if working, err := os.ReadFile(filepath.Join(projectPath, file)); err == nil && !info.IsBinary {
+		info.Working = string(working)
+		info.Regions = parseConflictRegions(info.Working)
+	}
+
+	return info, nil
+}
+
+// ResolveConflict resolves a conflicted file with the given strategy and marks it as resolved
+func (s *GitService) ResolveConflict(projectPath string, file string, resolution ConflictResolution) error {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	stages, err := conflictStages(repo, file)
+	if err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(projectPath, file)
+	var content []byte
+	switch resolution.Strategy {
+	case ResolveOurs, ResolveTheirs:
+		stage, side := index.OurMode, "--ours"
+		if resolution.Strategy == ResolveTheirs {
+			stage, side = index.TheirMode, "--theirs"
+		}
+
+		if _, ok := stages[stage]; !ok {
+			// The chosen side deleted the file
+			if _, err := runGit(projectPath, "rm", "--quiet", "--force", "--", file); err != nil {
+				return fmt.Errorf("failed to resolve conflict: %w", err)
+			}
+			return nil
+		}
+		// git checks the side out with its mode, executable bit and symbolic links included
+		if _, err := runGit(projectPath, "checkout", side, "--", file); err != nil {
+			return fmt.Errorf("failed to resolve conflict: %w", err)
+		}
+	case ResolveBoth:
+		working, err := os.ReadFile(fullPath)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		merged, ok := takeBothSides(string(working))
+		if !ok {
+			return fmt.Errorf("%s has no conflict regions to combine", file)
+		}
+		content = []byte(merged)
+	case ResolveContent:
+		content = []byte(resolution.Content)
+	default:
+		return fmt.Errorf("unknown conflict resolution strategy: %q", resolution.Strategy)
+	}
+
+	if content != nil {
+		// A symbolic link is replaced by a regular file, an executable file stays executable
+		mode := filemode.Regular
+		if info, err := os.Lstat(fullPath); err == nil && info.Mode().IsRegular() && info.Mode()&0111 != 0 {
+			mode = filemode.Executable
+		}
+		if err := writeWorktreeFile(fullPath, content, mode); err != nil {
+			return err
+		}
+	}
+
+	if _, err := runGit(projectPath, "add", "--", file); err != nil {
+		return fmt.Errorf("failed to mark conflict as resolved: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteMerge creates the merge commit once all conflicts are resolved.
+// An empty message keeps the message prepared by git.
+func (s *GitService) CompleteMerge(projectPath string, message string) error {
+	state, err := s.GetMergeState(projectPath)
+	if err != nil {
+		return err
+	}
+	if !state.InProgress {
+		return errors.New("no merge in progress")
+	}
+	if len(state.Conflicts) > 0 {
+		return fmt.Errorf("%d files still have conflicts", len(state.Conflicts))
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	before, err := currentRefState(repo)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"commit", "--no-edit"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	if _, err := runGit(projectPath, args...); err != nil {
+		return fmt.Errorf("failed to create merge commit: %w", err)
+	}
+
+	s.recordUndo(projectPath, repo, "merge", RestoreKeep, before)
+	return nil
+}
+
+// conflictedFiles returns the sorted paths that have unmerged entries in the index
+func conflictedFiles(repo *git.Repository) ([]string, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	files := []string{}
+	for _, entry := range idx.Entries {
+		if entry.Stage != 0 && !seen[entry.Name] {
+			seen[entry.Name] = true
+			files = append(files, entry.Name)
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// conflictStages returns the blob hash of each unmerged stage of a file
+func conflictStages(repo *git.Repository, file string) (map[index.Stage]plumbing.Hash, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+
+	stages := make(map[index.Stage]plumbing.Hash)
+	for _, entry := range idx.Entries {
+		if entry.Name == file && entry.Stage != 0 {
+			stages[entry.Stage] = entry.Hash
+		}
+	}
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("%s is not conflicted", file)
+	}
+
+	return stages, nil
+}
+
+// readGitDirFile returns the content of a file in the repository's git directory,
+// or an empty string if it does not exist
+func readGitDirFile(repo *git.Repository, name string) (string, error) {
+	dir, err := gitDir(repo)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	return string(content), nil
+}
+
+// parseConflictRegions finds the conflict marker blocks in a file
+func parseConflictRegions(content string) []ConflictRegion {
+	regions := []ConflictRegion{}
+
+	var current *ConflictRegion
+	var section *[]string
+	var ours, base, theirs []string
+	for i, line := range splitLines(content) {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<") && current == nil:
+			current = &ConflictRegion{
+				StartLine: i + 1,
+				OursLabel: strings.TrimSpace(strings.TrimPrefix(line, "<<<<<<<")),
+			}
+			ours, base, theirs = nil, nil, nil
+			section = &ours
+		case strings.HasPrefix(line, "|||||||") && current != nil:
+			section = &base
+		case strings.HasPrefix(line, "=======") && current != nil:
+			section = &theirs
+		case strings.HasPrefix(line, ">>>>>>>") && current != nil:
+			current.EndLine = i + 1
+			current.TheirsLabel = strings.TrimSpace(strings.TrimPrefix(line, ">>>>>>>"))
+			current.Ours = joinLines(ours)
+			current.Base = joinLines(base)
+			current.Theirs = joinLines(theirs)
+			regions = append(regions, *current)
+			current = nil
+		case current != nil:
+			*section = append(*section, line)
+		}
+	}
+
+	return regions
+}
+
+// takeBothSides replaces every conflict region with its ours lines followed by its
+// theirs lines. It reports false if the content has no conflict regions.
+func takeBothSides(content string) (string, bool) {
+	regions := parseConflictRegions(content)
+	if len(regions) == 0 {
+		return "", false
+	}
+
+	lines := splitLines(content)
+	var result []string
+	next := 0
+	for _, region := range regions {
+		result = append(result, lines[next:region.StartLine-1]...)
+		result = append(result, splitLines(region.Ours)...)
+		result = append(result, splitLines(region.Theirs)...)
+		next = region.EndLine
+	}
+	result = append(result, lines[next:]...)
+
+	merged := joinLines(result)
+	if !strings.HasSuffix(content, "\n") {
+		merged = strings.TrimSuffix(merged, "\n")
+	}
+	return merged, true
+}
@@ -0,0 +1,569 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PatchExportOptions describes the commits exported as format-patch files
+type PatchExportOptions struct {
+	Commits []string `json:"commits"` // Commits in the order they are applied, usually oldest first
+	Output  string   `json:"output"`  // Directory the patch files are written to, or the mbox file with Single
+	Single  bool     `json:"single"`  // Write all commits to a single mbox file instead of one file per commit
+}
+
+// DiffPatchOptions selects the uncommitted changes turned into a patch
+type DiffPatchOptions struct {
+	Files   []string `json:"files"`   // Files to include, all changed files when empty
+	Staged  bool     `json:"staged"`  // Use the staged changes only
+	Message string   `json:"message"` // Commit message of an exported mbox, unused for plain patches
+}
+
+// PatchApplyOptions describes a patch to apply and where it goes
+type PatchApplyOptions struct {
+	Path     string `json:"path"`     // Patch or mbox file to apply
+	Patch    string `json:"patch"`    // Patch text, used when Path is empty
+	Staged   bool   `json:"staged"`   // Apply to the index
+	Worktree bool   `json:"worktree"` // Apply to the working tree, the default when Staged is not set either
+	ThreeWay bool   `json:"threeWay"` // Fall back to a three-way merge that leaves conflicts, this uses the index
+	Check    bool   `json:"check"`    // Only report whether the patch applies, changing nothing
+}
+
+// PatchApplyResult describes the outcome of applying a patch
+type PatchApplyResult struct {
+	Applied   bool              `json:"applied"`   // Whether the patch was applied, possibly with conflicts
+	Error     string            `json:"error"`     // Why nothing was applied
+	Files     []PatchFileResult `json:"files"`     // Files of the patch
+	Conflicts []string          `json:"conflicts"` // Files left with conflict markers by the three-way fallback
+}
+
+// PatchFileResult describes one file of a patch
+type PatchFileResult struct {
+	Path       string      `json:"path"`
+	OldPath    string      `json:"oldPath"` // Path before the change, for renames and copies
+	Added      int         `json:"added"`
+	Deleted    int         `json:"deleted"`
+	Binary     bool        `json:"binary"`
+	Applies    bool        `json:"applies"`    // Whether the changes of the file apply on their own
+	Conflicted bool        `json:"conflicted"` // Whether the three-way fallback left conflicts in the file
+	Error      string      `json:"error"`      // Why the file does not apply
+	Hunks      []PatchHunk `json:"hunks"`      // Hunks of the file, checked one by one when the file does not apply
+}
+
+// PatchHunk is one "@@" section of a file in a patch
+type PatchHunk struct {
+	Header   string `json:"header"` // The "@@ -a,b +c,d @@" line
+	OldStart int    `json:"oldStart"`
+	OldLines int    `json:"oldLines"`
+	NewStart int    `json:"newStart"`
+	NewLines int    `json:"newLines"`
+	Applies  bool   `json:"applies"`
+}
+
+// patchFile is a file section of a patch, as the header lines and the lines of each hunk
+type patchFile struct {
+	result  PatchFileResult
+	header  []string
+	hunks   [][]string
+	oldPath string // Path of the "---" line
+	newPath string // Path of the "+++" line
+}
+
+// hunkHeaderPattern matches "@@ -oldStart[,oldLines] +newStart[,newLines] @@"
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ExportPatches writes commits as format-patch files that can be applied with "git am",
+// and returns the paths of the written files. Merge commits are skipped.
+func (s *GitService) ExportPatches(projectPath string, opts PatchExportOptions) ([]string, error) {
+	if len(opts.Commits) == 0 {
+		return nil, errors.New("no commits to export")
+	}
+	if opts.Output == "" {
+		return nil, errors.New("no output path")
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	// format-patch outputs unsorted commits in reverse order
+	args := []string{"format-patch", "--no-walk=unsorted"}
+	if opts.Single {
+		args = append(args, "--stdout")
+	} else {
+		args = append(args, "-o", opts.Output)
+	}
+	for i := len(opts.Commits) - 1; i >= 0; i-- {
+		commit, err := resolveCommit(repo, opts.Commits[i])
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, commit.Hash.String())
+	}
+
+	out, err := runGit(projectPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export patches: %w", err)
+	}
+
+	if opts.Single {
+		if err := os.WriteFile(opts.Output, []byte(out), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write patch: %w", err)
+		}
+		return []string{opts.Output}, nil
+	}
+
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(projectPath, line)
+		}
+		paths = append(paths, line)
+	}
+	return paths, nil
+}
+
+// GetDiffPatch returns uncommitted changes as a patch, for copying them. Like the diff
+// view, these are the staged changes or the working tree changes that are not staged,
+// untracked files included.
+func (s *GitService) GetDiffPatch(projectPath string, opts DiffPatchOptions) (string, error) {
+	if opts.Staged {
+		out, err := runGit(projectPath, append([]string{"diff", "--cached", "--binary", "--"}, opts.Files...)...)
+		if err != nil {
+			return "", fmt.Errorf("failed to get patch: %w", err)
+		}
+		return out, nil
+	}
+
+	// Untracked files only show up in the diff once they are in the index, so they are
+	// added with intent-to-add to a copy of it
+	entries, err := s.status(projectPath)
+	if err != nil {
+		return "", err
+	}
+	selected := make(map[string]bool)
+	for _, file := range opts.Files {
+		selected[filepath.ToSlash(file)] = true
+	}
+	var untracked []string
+	for _, entry := range entries {
+		if entry.Worktree == StateUntracked && (len(selected) == 0 || selected[entry.Path]) {
+			untracked = append(untracked, entry.Path)
+		}
+	}
+
+	var env []string
+	if len(untracked) > 0 {
+		indexFile, cleanup, err := tempIndex(projectPath)
+		if err != nil {
+			return "", err
+		}
+		defer cleanup()
+		env = []string{"GIT_INDEX_FILE=" + indexFile}
+
+		if _, err := runGitEnv(projectPath, env, append([]string{"add", "--intent-to-add", "--"}, untracked...)...); err != nil {
+			return "", fmt.Errorf("failed to add untracked files: %w", err)
+		}
+	}
+
+	out, err := runGitEnv(projectPath, env, append([]string{"diff", "--binary", "--"}, opts.Files...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get patch: %w", err)
+	}
+	return out, nil
+}
+
+// ExportDiffPatch returns uncommitted changes as a format-patch mbox with the given
+// message, so they can be applied with "git am" on top of HEAD, and writes it to output
+// unless it is empty. These are the staged changes, or all the changes of the working
+// tree including untracked files.
+func (s *GitService) ExportDiffPatch(projectPath string, opts DiffPatchOptions, output string) (string, error) {
+	if strings.TrimSpace(opts.Message) == "" {
+		return "", errors.New("a message is required")
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	current, err := currentRefState(repo)
+	if err != nil {
+		return "", err
+	}
+
+	// The changes are committed to a dangling commit, which format-patch turns into an mbox
+	indexFile, cleanup, err := tempIndex(projectPath)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	env := []string{"GIT_INDEX_FILE=" + indexFile}
+
+	if !opts.Staged {
+		if _, err := runGitEnv(projectPath, env, "add", "--all", "--", ":/"); err != nil {
+			return "", fmt.Errorf("failed to add changes: %w", err)
+		}
+	}
+	tree, err := runGitEnv(projectPath, env, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to write tree: %w", err)
+	}
+	tree = strings.TrimSpace(tree)
+
+	args := []string{"commit-tree", tree}
+	if !current.hash.IsZero() {
+		args = append(args, "-p", current.hash.String())
+	}
+	hash, err := runGitInput(projectPath, nil, []byte(opts.Message), args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	args = []string{"format-patch", "--stdout", "-1", strings.TrimSpace(hash)}
+	if current.hash.IsZero() {
+		args = append(args, "--root")
+	}
+	out, err := runGit(projectPath, append(append(args, "--"), opts.Files...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to export patch: %w", err)
+	}
+	if !strings.Contains(out, "\ndiff --git ") {
+		return "", errors.New("no changes to export")
+	}
+
+	if output != "" {
+		if err := os.WriteFile(output, []byte(out), 0644); err != nil {
+			return "", fmt.Errorf("failed to write patch: %w", err)
+		}
+	}
+	return out, nil
+}
+
+// ApplyPatch applies a patch or an mbox file to the working tree, the index or both,
+// without committing. Either the whole patch is applied or nothing is, and the result
+// reports the files and hunks that do not apply, unless the three-way fallback then
+// applies it with conflicts.
+func (s *GitService) ApplyPatch(projectPath string, opts PatchApplyOptions) (*PatchApplyResult, error) {
+	patch := []byte(opts.Patch)
+	if opts.Path != "" {
+		content, err := os.ReadFile(opts.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read patch: %w", err)
+		}
+		patch = content
+	}
+
+	files := parsePatch(patch)
+	if len(files) == 0 {
+		return nil, errors.New("no changes found in the patch")
+	}
+
+	args := []string{"apply"}
+	switch {
+	case opts.Staged && opts.Worktree:
+		args = append(args, "--index")
+	case opts.Staged:
+		args = append(args, "--cached")
+	}
+
+	// Applying without the three-way fallback is atomic, so it is tried first to find out what does not apply
+	checkArgs := append(append([]string{}, args...), "--check")
+	if opts.Check {
+		args = checkArgs
+	}
+	_, applyErr := runGitInput(projectPath, nil, patch, args...)
+	if !opts.Check {
+		s.invalidateStatus(projectPath)
+	}
+
+	result := &PatchApplyResult{Files: []PatchFileResult{}, Conflicts: []string{}}
+	if applyErr == nil {
+		result.Applied = !opts.Check
+		for _, file := range files {
+			file.result.Applies = true
+			for i := range file.result.Hunks {
+				file.result.Hunks[i].Applies = true
+			}
+			result.Files = append(result.Files, file.result)
+		}
+		return result, nil
+	}
+
+	// Check the files and their hunks one by one to report what fails
+	for _, file := range files {
+		_, err := runGitInput(projectPath, nil, file.section(-1), checkArgs...)
+		file.result.Applies = err == nil
+		if err != nil {
+			file.result.Error = applyErrorMessage(err)
+		}
+		for i := range file.result.Hunks {
+			if err == nil {
+				file.result.Hunks[i].Applies = true
+				continue
+			}
+			_, hunkErr := runGitInput(projectPath, nil, file.section(i), checkArgs...)
+			file.result.Hunks[i].Applies = hunkErr == nil
+		}
+	}
+
+	if !opts.ThreeWay || opts.Check {
+		result.Error = applyErrorMessage(applyErr)
+		for _, file := range files {
+			result.Files = append(result.Files, file.result)
+		}
+		return result, nil
+	}
+
+	// The three-way fallback applies the patch but exits with an error on conflicts
+	_, mergeErr := runGitInput(projectPath, nil, patch, append(args, "--3way")...)
+	s.invalidateStatus(projectPath)
+	entries, err := s.status(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	conflicted := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Unmerged {
+			conflicted[entry.Path] = true
+		}
+	}
+	for _, file := range files {
+		if conflicted[file.result.Path] {
+			file.result.Conflicted = true
+			result.Conflicts = append(result.Conflicts, file.result.Path)
+		}
+		result.Files = append(result.Files, file.result)
+	}
+
+	if mergeErr != nil && len(result.Conflicts) == 0 {
+		result.Error = applyErrorMessage(mergeErr)
+		return result, nil
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// applyErrorMessage returns the messages of a failed "git apply", without the command prefix
+func applyErrorMessage(err error) string {
+	return strings.TrimPrefix(err.Error(), "git apply failed: ")
+}
+
+// section returns the file as a patch with all its hunks, or only the given one
+func (f *patchFile) section(hunk int) []byte {
+	lines := append([]string{}, f.header...)
+	for i, body := range f.hunks {
+		if hunk < 0 || hunk == i {
+			lines = append(lines, body...)
+		}
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// parsePatch splits a patch, which can be an mbox of several commits, into its file sections.
+// Mail headers, commit messages and signatures between them are skipped.
+func parsePatch(patch []byte) []*patchFile {
+	var files []*patchFile
+	var file *patchFile
+	inHeader := false
+
+	lines := strings.Split(strings.TrimSuffix(string(patch), "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			file = &patchFile{result: PatchFileResult{Hunks: []PatchHunk{}}}
+			files = append(files, file)
+			inHeader = true
+			if _, b, ok := splitGitDiffPaths(strings.TrimPrefix(line, "diff --git ")); ok {
+				file.result.Path = b
+			}
+			file.header = append(file.header, line)
+			continue
+		case strings.HasPrefix(line, "--- ") && !inHeader && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			// A file of a plain diff, without a git header
+			file = &patchFile{result: PatchFileResult{Hunks: []PatchHunk{}}}
+			files = append(files, file)
+			inHeader = true
+		}
+		if file == nil {
+			continue
+		}
+
+		if strings.HasPrefix(line, "@@") {
+			match := hunkHeaderPattern.FindStringSubmatch(line)
+			if match == nil {
+				inHeader = false
+				continue
+			}
+			hunk := PatchHunk{
+				Header:   line,
+				OldStart: atoiDefault(match[1], 1),
+				OldLines: atoiDefault(match[2], 1),
+				NewStart: atoiDefault(match[3], 1),
+				NewLines: atoiDefault(match[4], 1),
+			}
+
+			// The hunk ends once the line counts of its header are reached
+			body := []string{line}
+			oldLeft, newLeft := hunk.OldLines, hunk.NewLines
+			for i+1 < len(lines) && (oldLeft > 0 || newLeft > 0 || strings.HasPrefix(lines[i+1], `\`)) {
+				i++
+				body = append(body, lines[i])
+				switch {
+				case strings.HasPrefix(lines[i], "+"):
+					newLeft--
+					file.result.Added++
+				case strings.HasPrefix(lines[i], "-"):
+					oldLeft--
+					file.result.Deleted++
+				case strings.HasPrefix(lines[i], `\`):
+				default:
+					// Context lines, which mailers may strip down to an empty line
+					oldLeft--
+					newLeft--
+				}
+			}
+
+			file.hunks = append(file.hunks, body)
+			file.result.Hunks = append(file.result.Hunks, hunk)
+			inHeader = false
+			continue
+		}
+		if !inHeader {
+			continue
+		}
+
+		file.header = append(file.header, line)
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			file.oldPath = patchPath(strings.TrimPrefix(line, "--- "))
+		case strings.HasPrefix(line, "+++ "):
+			file.newPath = patchPath(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "rename from "), strings.HasPrefix(line, "copy from "):
+			file.result.OldPath = unquotePath(line[strings.Index(line, "from ")+5:])
+		case strings.HasPrefix(line, "rename to "), strings.HasPrefix(line, "copy to "):
+			file.result.Path = unquotePath(line[strings.Index(line, "to ")+3:])
+		case line == "GIT binary patch", strings.HasPrefix(line, "Binary files "):
+			file.result.Binary = true
+		}
+	}
+
+	for _, file := range files {
+		switch {
+		case file.newPath != "":
+			file.result.Path = file.newPath
+		case file.result.Path == "":
+			// Deleted files only have their old path
+			file.result.Path = file.oldPath
+		}
+	}
+	return files
+}
+
+// splitGitDiffPaths splits the "a/old b/new" paths of a "diff --git" line
+func splitGitDiffPaths(paths string) (string, string, bool) {
+	if strings.HasPrefix(paths, `"`) {
+		end := strings.Index(paths[1:], `" `)
+		if end < 0 {
+			return "", "", false
+		}
+		return patchPath(paths[:end+2]), patchPath(paths[end+3:]), true
+	}
+	// Without quotes both paths are the same unless the file is renamed, in which
+	// case the header has the rename lines
+	if len(paths)%2 == 1 {
+		half := len(paths) / 2
+		if paths[half] == ' ' && paths[2:half] == paths[half+3:] {
+			return patchPath(paths[:half]), patchPath(paths[half+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// patchPath returns the path of a "---" or "+++" line without the "a/" or "b/" prefix,
+// or "" for /dev/null
+func patchPath(path string) string {
+	// Plain diffs may follow the path with a tab and a timestamp
+	if tab := strings.IndexByte(path, '\t'); tab >= 0 {
+		path = path[:tab]
+	}
+	path = unquotePath(path)
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		path = path[2:]
+	}
+	return path
+}
+
+// unquotePath decodes a path quoted by git because of special characters
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
+
+// atoiDefault parses a number, returning def for an empty string
+func atoiDefault(value string, def int) int {
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// tempIndex copies the index of a repository to a temporary file, so changes can be
+// staged to it with GIT_INDEX_FILE without touching the real index. The returned
+// function removes the copy.
+func tempIndex(projectPath string) (string, func(), error) {
+	out, err := runGit(projectPath, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find index: %w", err)
+	}
+	indexPath := strings.TrimSpace(out)
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(projectPath, indexPath)
+	}
+
+	file, err := os.CreateTemp("", "edit4i-index-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	tmpPath := file.Name()
+	cleanup := func() { os.Remove(tmpPath) }
+	defer file.Close()
+
+	content, err := os.ReadFile(indexPath)
+	switch {
+	case err == nil:
+		if _, err := file.Write(content); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("failed to write temporary index: %w", err)
+		}
+	case os.IsNotExist(err):
+		// git refuses an empty index file, it creates the index itself
+		cleanup()
+	default:
+		cleanup()
+		return "", nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	return tmpPath, cleanup, nil
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// noCommitPickFile records, in the git directory, a cherry-pick or revert without
+// commits that stopped on conflicts. Git keeps no state for those: without a commit
+// to make it writes neither CHERRY_PICK_HEAD nor REVERT_HEAD.
+const noCommitPickFile = "EDIT4I_NO_COMMIT_PICK"
+
+// noCommitPick is a cherry-pick or revert without commits that stopped on conflicts
+type noCommitPick struct {
+	operation string
+	head      string   // HEAD when the operation started, which it does not move
+	current   string   // Commit whose changes conflicted
+	remaining []string // Commits still to apply
+}
+
+// PickOptions contains options for cherry-picking or reverting commits
+type PickOptions struct {
+	NoCommit bool `json:"noCommit"` // Leave the changes staged instead of committing them
+}
+
+// PickState describes a cherry-pick or revert in progress
+type PickState struct {
+	InProgress bool     `json:"inProgress"`
+	Operation  string   `json:"operation"` // "cherryPick" or "revert"
+	Current    string   `json:"current"`   // Commit the operation stopped at
+	Remaining  []string `json:"remaining"` // Commits still to apply
+	Head       string   `json:"head"`      // Current HEAD
+	Conflicts  []string `json:"conflicts"` // Files with unresolved conflicts
+}
+
+// CherryPick applies the changes of the given commits on top of the current branch,
+// in the order they are given. Conflicts are not an error: the cherry-pick stops and
+// the returned state lists the conflicted files.
+func (s *GitService) CherryPick(projectPath string, commits []string, opts PickOptions) (*PickState, error) {
+	hashes, err := s.resolvePickCommits(projectPath, commits)
+	if err != nil {
+		return nil, err
+	}
+	if opts.NoCommit {
+		return s.pickNoCommit(projectPath, OpCherryPick, hashes)
+	}
+
+	pickErr := s.backend(OpCherryPick).CherryPick(projectPath, hashes, opts.NoCommit)
+	return s.pickResult(projectPath, "cherry-pick", pickErr)
+}
+
+// Revert creates commits reverting the changes of the given commits, in the order
+// they are given, so several commits are usually passed newest first. Conflicts are
+// not an error: the revert stops and the returned state lists the conflicted files.
+func (s *GitService) Revert(projectPath string, commits []string, opts PickOptions) (*PickState, error) {
+	hashes, err := s.resolvePickCommits(projectPath, commits)
+	if err != nil {
+		return nil, err
+	}
+	if opts.NoCommit {
+		return s.pickNoCommit(projectPath, OpRevert, hashes)
+	}
+
+	revertErr := s.backend(OpRevert).Revert(projectPath, hashes, opts.NoCommit)
+	return s.pickResult(projectPath, "revert", revertErr)
+}
+
+// ContinuePick continues a stopped cherry-pick or revert once conflicts are resolved
+func (s *GitService) ContinuePick(projectPath string) (*PickState, error) {
+	state, err := s.GetPickState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if !state.InProgress {
+		return nil, errors.New("no cherry-pick or revert in progress")
+	}
+	if len(state.Conflicts) > 0 {
+		return nil, fmt.Errorf("%d files still have conflicts", len(state.Conflicts))
+	}
+
+	pick, dir, err := s.noCommitPick(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if pick != nil {
+		// The resolved changes stay staged, the remaining commits are applied on top
+		return s.applyNoCommit(projectPath, dir, *pick, pick.remaining)
+	}
+
+	command := pickCommand(state.Operation)
+	_, err = runGitEnv(projectPath, []string{"GIT_EDITOR=true"}, command, "--continue")
+	return s.pickResult(projectPath, command, err)
+}
+
+// AbortPick aborts the cherry-pick or revert in progress and restores the state from before it started
+func (s *GitService) AbortPick(projectPath string) error {
+	state, err := s.GetPickState(projectPath)
+	if err != nil {
+		return err
+	}
+	if !state.InProgress {
+		return errors.New("no cherry-pick or revert in progress")
+	}
+
+	command := pickCommand(state.Operation)
+	pick, dir, err := s.noCommitPick(projectPath)
+	if err != nil {
+		return err
+	}
+	if pick != nil {
+		// Nothing was committed, so the index and working tree go back to HEAD,
+		// keeping the changes of files the operation did not touch
+		if _, err := runGit(projectPath, "reset", "--merge"); err != nil {
+			return fmt.Errorf("failed to abort %s: %w", command, err)
+		}
+		return removeNoCommitPick(dir)
+	}
+
+	if _, err := runGit(projectPath, command, "--abort"); err != nil {
+		return fmt.Errorf("failed to abort %s: %w", command, err)
+	}
+	return nil
+}
+
+// GetPickState returns whether a cherry-pick or revert is in progress and where it stopped
+func (s *GitService) GetPickState(projectPath string) (*PickState, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	state := &PickState{Remaining: []string{}}
+
+	if head, err := repo.Head(); err == nil {
+		state.Head = head.Hash().String()
+	}
+	if state.Conflicts, err = conflictedFiles(repo); err != nil {
+		return nil, err
+	}
+
+	// A revert without commits leaves REVERT_HEAD, but continuing must not commit
+	pick, err := readNoCommitPick(repo, state.Head)
+	if err != nil {
+		return nil, err
+	}
+	if pick != nil {
+		state.InProgress = true
+		state.Operation = pick.operation
+		state.Current = pick.current
+		state.Remaining = append(state.Remaining, pick.remaining...)
+		return state, nil
+	}
+
+	for name, operation := range map[string]string{
+		"CHERRY_PICK_HEAD": OpCherryPick,
+		"REVERT_HEAD":      OpRevert,
+	} {
+		content, err := readGitDirFile(repo, name)
+		if err != nil {
+			return nil, err
+		}
+		if content != "" {
+			state.InProgress = true
+			state.Operation = operation
+			state.Current = strings.TrimSpace(content)
+		}
+	}
+
+	// With several commits git keeps the remaining ones in its sequencer,
+	// which outlives the *_HEAD file once the stopped commit is committed
+	todo, err := readGitDirFile(repo, "sequencer/todo")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(todo, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		operation := OpCherryPick
+		if fields[0] == "revert" {
+			operation = OpRevert
+		}
+		if !state.InProgress {
+			state.InProgress = true
+			state.Operation = operation
+		}
+
+		commit, err := resolveCommit(repo, fields[1])
+		if err != nil {
+			return nil, err
+		}
+		if commit.Hash.String() != state.Current {
+			state.Remaining = append(state.Remaining, commit.Hash.String())
+		}
+	}
+
+	return state, nil
+}
+
+// pickNoCommit cherry-picks or reverts commits without committing them. The commits
+// are applied one at a time: git cannot continue a sequence of them after conflicts.
+func (s *GitService) pickNoCommit(projectPath string, operation string, commits []string) (*PickState, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	dir, err := gitDir(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	pick := noCommitPick{operation: operation, head: head.Hash().String()}
+	return s.applyNoCommit(projectPath, dir, pick, commits)
+}
+
+// applyNoCommit applies commits without committing them, recording where it stopped
+// when one of them conflicts
+func (s *GitService) applyNoCommit(projectPath string, dir string, pick noCommitPick, commits []string) (*PickState, error) {
+	apply := s.backend(OpCherryPick).CherryPick
+	if pick.operation == OpRevert {
+		apply = s.backend(OpRevert).Revert
+	}
+
+	for i, hash := range commits {
+		applyErr := apply(projectPath, []string{hash}, true)
+		if applyErr == nil {
+			continue
+		}
+
+		pick.current = hash
+		pick.remaining = commits[i+1:]
+		if err := writeNoCommitPick(dir, pick); err != nil {
+			return nil, err
+		}
+		state, err := s.pickResult(projectPath, pickCommand(pick.operation), applyErr)
+		if err != nil {
+			// Failed without conflicts, there is nothing to continue
+			removeNoCommitPick(dir)
+			return nil, err
+		}
+		return state, nil
+	}
+
+	// The changes stay staged, and nothing is left to continue or abort
+	command := pickCommand(pick.operation)
+	if _, err := runGit(projectPath, command, "--quit"); err != nil {
+		return nil, fmt.Errorf("failed to finish %s: %w", command, err)
+	}
+	if err := removeNoCommitPick(dir); err != nil {
+		return nil, err
+	}
+	return s.GetPickState(projectPath)
+}
+
+// noCommitPick returns the cherry-pick or revert without commits in progress, nil
+// when there is none, and the git directory it is recorded in
+func (s *GitService) noCommitPick(projectPath string) (*noCommitPick, string, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	dir, err := gitDir(repo)
+	if err != nil {
+		return nil, "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	pick, err := readNoCommitPick(repo, head.Hash().String())
+	return pick, dir, err
+}
+
+// readNoCommitPick reads the recorded cherry-pick or revert without commits. A record
+// left from before HEAD moved, by a commit or a checkout, no longer applies.
+func readNoCommitPick(repo *git.Repository, head string) (*noCommitPick, error) {
+	content, err := readGitDirFile(repo, noCommitPickFile)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(content)
+	if len(fields) < 3 || fields[1] != head {
+		return nil, nil
+	}
+
+	return &noCommitPick{
+		operation: fields[0],
+		head:      fields[1],
+		current:   fields[2],
+		remaining: fields[3:],
+	}, nil
+}
+
+// writeNoCommitPick records a cherry-pick or revert without commits that stopped on conflicts
+func writeNoCommitPick(dir string, pick noCommitPick) error {
+	fields := append([]string{pick.operation, pick.head, pick.current}, pick.remaining...)
+	if err := os.WriteFile(filepath.Join(dir, noCommitPickFile), []byte(strings.Join(fields, " ")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record %s state: %w", pickCommand(pick.operation), err)
+	}
+	return nil
+}
+
+// removeNoCommitPick forgets the recorded cherry-pick or revert without commits
+func removeNoCommitPick(dir string) error {
+	if err := os.Remove(filepath.Join(dir, noCommitPickFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", noCommitPickFile, err)
+	}
+	return nil
+}
+
+// pickResult returns the state after a cherry-pick or revert command. A command
+// that failed only reports an error when it did not stop on conflicts.
+func (s *GitService) pickResult(projectPath string, command string, cmdErr error) (*PickState, error) {
+	state, err := s.GetPickState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if cmdErr != nil && len(state.Conflicts) == 0 {
+		return nil, fmt.Errorf("failed to %s: %w", command, cmdErr)
+	}
+	return state, nil
+}
+
+// resolvePickCommits resolves the revisions to cherry-pick or revert to commit hashes
+func (s *GitService) resolvePickCommits(projectPath string, commits []string) ([]string, error) {
+	if len(commits) == 0 {
+		return nil, errors.New("no commits given")
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	hashes := make([]string, len(commits))
+	for i, revision := range commits {
+		commit, err := resolveCommit(repo, revision)
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = commit.Hash.String()
+	}
+
+	return hashes, nil
+}
+
+// pickCommand returns the git command of a cherry-pick or revert operation
+func pickCommand(operation string) string {
+	if operation == OpRevert {
+		return "revert"
+	}
+	return "cherry-pick"
+}
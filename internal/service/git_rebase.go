@@ -0,0 +1,395 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Rebase plan actions
+const (
+	RebasePick   = "pick"   // Use the commit
+	RebaseReword = "reword" // Use the commit with a new message
+	RebaseEdit   = "edit"   // Use the commit and stop to amend it
+	RebaseSquash = "squash" // Meld into the previous commit, combining the messages
+	RebaseFixup  = "fixup"  // Meld into the previous commit, keeping its message
+	RebaseDrop   = "drop"   // Remove the commit
+)
+
+// Reasons for an interactive rebase to stop
+const (
+	RebaseStopConflict = "conflict" // Applying the current step produced conflicts
+	RebaseStopEdit     = "edit"     // An edit step was reached
+)
+
+// rebaseMessagePrefix names the files holding replacement commit messages inside
+// the rebase state directory, where git removes them when the rebase ends
+const rebaseMessagePrefix = "edit4i-message-"
+
+// RebaseStep is a single line of an interactive rebase plan
+type RebaseStep struct {
+	Action     string    `json:"action"`     // One of "pick", "reword", "edit", "squash", "fixup" or "drop"
+	Hash       string    `json:"hash"`       // Commit the step applies
+	Message    string    `json:"message"`    // Current message of the commit
+	NewMessage string    `json:"newMessage"` // Replacement message, required for reword and optional for squash and fixup
+	Author     string    `json:"author"`
+	Date       time.Time `json:"date"`
+}
+
+// RebasePlan is an editable interactive rebase plan, steps are in the order they are applied
+type RebasePlan struct {
+	Base  string       `json:"base"` // Commit the steps are replayed onto, empty to rebase from the root commit
+	Steps []RebaseStep `json:"steps"`
+}
+
+// RebaseState describes an interactive rebase in progress
+type RebaseState struct {
+	InProgress bool         `json:"inProgress"`
+	Branch     string       `json:"branch"`     // Branch being rebased
+	Onto       string       `json:"onto"`       // Commit the steps are replayed onto
+	OrigHead   string       `json:"origHead"`   // Branch tip before the rebase started
+	Head       string       `json:"head"`       // Current HEAD
+	Done       []RebaseStep `json:"done"`       // Steps already applied, including the current one
+	Current    *RebaseStep  `json:"current"`    // Step the rebase stopped at
+	Remaining  []RebaseStep `json:"remaining"`  // Steps still to apply
+	StopReason string       `json:"stopReason"` // "conflict", "edit" or empty when the rebase can continue
+	Conflicts  []string     `json:"conflicts"`  // Files with unresolved conflicts
+}
+
+// GetRebasePlan returns a plan picking every commit between base and HEAD, oldest first.
+// Merge commits are left out, as git does for interactive rebases. An empty base
+// includes the whole history of the current branch.
+func (s *GitService) GetRebasePlan(projectPath string, base string) (*RebasePlan, error) {
+	if strings.HasPrefix(base, "-") {
+		return nil, fmt.Errorf("invalid base revision: %q", base)
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	rangeArg := "HEAD"
+	if base != "" {
+		baseCommit, err := resolveCommit(repo, base)
+		if err != nil {
+			return nil, err
+		}
+		base = baseCommit.Hash.String()
+		rangeArg = base + "..HEAD"
+	}
+
+	out, err := runGit(projectPath, "rev-list", "--reverse", "--no-merges", rangeArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	plan := &RebasePlan{Base: base, Steps: []RebaseStep{}}
+	for _, hash := range strings.Fields(out) {
+		step, err := rebaseStep(repo, RebasePick, hash)
+		if err != nil {
+			return nil, err
+		}
+		plan.Steps = append(plan.Steps, *step)
+	}
+
+	return plan, nil
+}
+
+// StartRebase starts an interactive rebase executing the plan. Local changes are
+// stashed for the duration of the rebase and restored when it ends or is aborted.
+// Conflicts and edit steps are not errors: the rebase stops and the returned state
+// describes where it stopped.
+func (s *GitService) StartRebase(projectPath string, plan RebasePlan) (*RebaseState, error) {
+	if strings.HasPrefix(plan.Base, "-") {
+		return nil, fmt.Errorf("invalid base revision: %q", plan.Base)
+	}
+	if len(plan.Steps) == 0 {
+		return nil, errors.New("rebase plan has no steps")
+	}
+
+	state, err := s.GetRebaseState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if state.InProgress {
+		return nil, errors.New("a rebase is already in progress")
+	}
+
+	// The todo list and replacement messages are prepared in a temporary directory
+	// the backend hands to the rebase
+	tmpDir, err := os.MkdirTemp("", "edit4i-rebase-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todo, messages, err := rebaseTodo(plan.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replacement messages follow the commit convention like new commits, the
+	// commit-msg hook checks them as the rebase amends each commit
+	convention, err := s.GetCommitConvention(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range plan.Steps {
+		if step.NewMessage == "" || step.Action == RebaseDrop {
+			continue
+		}
+		if _, err := convention.enforce(step.NewMessage); err != nil {
+			return nil, fmt.Errorf("new message of %s: %w", step.Hash, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "git-rebase-todo"), []byte(todo), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write rebase plan: %w", err)
+	}
+	for name, message := range messages {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(message), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write commit message: %w", err)
+		}
+	}
+
+	rebaseErr := s.backend(OpRebase).Rebase(projectPath, plan.Base, tmpDir)
+	return s.rebaseResult(projectPath, "start rebase", rebaseErr)
+}
+
+// ContinueRebase continues a stopped rebase once conflicts are resolved or an edit step is done
+func (s *GitService) ContinueRebase(projectPath string) (*RebaseState, error) {
+	state, err := s.GetRebaseState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if !state.InProgress {
+		return nil, errors.New("no rebase in progress")
+	}
+	if len(state.Conflicts) > 0 {
+		return nil, fmt.Errorf("%d files still have conflicts", len(state.Conflicts))
+	}
+
+	err = s.backend(OpRebase).ContinueRebase(projectPath, false)
+	return s.rebaseResult(projectPath, "continue rebase", err)
+}
+
+// SkipRebase skips the step the rebase stopped at and continues with the next one
+func (s *GitService) SkipRebase(projectPath string) (*RebaseState, error) {
+	err := s.backend(OpRebase).ContinueRebase(projectPath, true)
+	return s.rebaseResult(projectPath, "skip rebase step", err)
+}
+
+// AbortRebase aborts the rebase in progress, restoring the branch, index and
+// working tree, including stashed local changes, to their state before it started
+func (s *GitService) AbortRebase(projectPath string) error {
+	if err := s.backend(OpRebase).AbortRebase(projectPath); err != nil {
+		return fmt.Errorf("failed to abort rebase: %w", err)
+	}
+	return nil
+}
+
+// rebaseResult returns the state after a rebase command. A command that failed
+// only reports an error when it did not leave a stopped rebase behind.
+func (s *GitService) rebaseResult(projectPath string, action string, cmdErr error) (*RebaseState, error) {
+	state, err := s.GetRebaseState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if cmdErr != nil && state.StopReason == "" {
+		return nil, fmt.Errorf("failed to %s: %w", action, cmdErr)
+	}
+	return state, nil
+}
+
+// GetRebaseState returns whether an interactive rebase is in progress and where it stopped
+func (s *GitService) GetRebaseState(projectPath string) (*RebaseState, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	state := &RebaseState{
+		Done:      []RebaseStep{},
+		Remaining: []RebaseStep{},
+	}
+
+	if head, err := repo.Head(); err == nil {
+		state.Head = head.Hash().String()
+	}
+	if state.Conflicts, err = conflictedFiles(repo); err != nil {
+		return nil, err
+	}
+
+	headName, err := readGitDirFile(repo, "rebase-merge/head-name")
+	if err != nil {
+		return nil, err
+	}
+	if headName == "" {
+		return state, nil
+	}
+
+	state.InProgress = true
+	state.Branch = plumbing.ReferenceName(strings.TrimSpace(headName)).Short()
+	for name, target := range map[string]*string{
+		"rebase-merge/onto":      &state.Onto,
+		"rebase-merge/orig-head": &state.OrigHead,
+	} {
+		content, err := readGitDirFile(repo, name)
+		if err != nil {
+			return nil, err
+		}
+		*target = strings.TrimSpace(content)
+	}
+
+	for name, target := range map[string]*[]RebaseStep{
+		"rebase-merge/done":            &state.Done,
+		"rebase-merge/git-rebase-todo": &state.Remaining,
+	} {
+		content, err := readGitDirFile(repo, name)
+		if err != nil {
+			return nil, err
+		}
+		if *target, err = parseRebaseTodo(repo, content); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(state.Done) > 0 {
+		state.Current = &state.Done[len(state.Done)-1]
+	}
+
+	amend, err := readGitDirFile(repo, "rebase-merge/amend")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(state.Conflicts) > 0:
+		state.StopReason = RebaseStopConflict
+	case amend != "":
+		state.StopReason = RebaseStopEdit
+	}
+
+	return state, nil
+}
+
+// rebaseTodo renders plan steps as a git todo list. Replacement messages are
+// applied by amending the commit right after its step, they are returned by file name.
+func rebaseTodo(steps []RebaseStep) (string, map[string]string, error) {
+	var todo strings.Builder
+	messages := make(map[string]string)
+
+	for i, step := range steps {
+		if step.Hash == "" || strings.ContainsAny(step.Hash, " \t\n") {
+			return "", nil, fmt.Errorf("invalid commit in step %d: %q", i+1, step.Hash)
+		}
+
+		action := step.Action
+		switch action {
+		case RebasePick, RebaseEdit, RebaseDrop:
+		case RebaseReword:
+			if strings.TrimSpace(step.NewMessage) == "" {
+				return "", nil, fmt.Errorf("step %d rewords %s without a new message", i+1, step.Hash)
+			}
+			// The message is replaced by the amend below, no need for git to ask for it
+			action = RebasePick
+		case RebaseSquash, RebaseFixup:
+			if todo.Len() == 0 {
+				return "", nil, fmt.Errorf("step %d cannot %s without a previous commit", i+1, step.Action)
+			}
+		default:
+			return "", nil, fmt.Errorf("unknown rebase action in step %d: %q", i+1, step.Action)
+		}
+
+		fmt.Fprintf(&todo, "%s %s\n", action, step.Hash)
+
+		if step.NewMessage != "" && step.Action != RebaseDrop {
+			name := rebaseMessagePrefix + strconv.Itoa(i)
+			messages[name] = step.NewMessage
+			fmt.Fprintf(&todo, "exec git commit --amend --quiet --allow-empty -F \"$(git rev-parse --git-path rebase-merge/%s)\"\n", name)
+		}
+	}
+
+	return todo.String(), messages, nil
+}
+
+// parseRebaseTodo parses a git todo list into plan steps. The amend commands
+// added by rebaseTodo are folded back into the replacement message of their step.
+func parseRebaseTodo(repo *git.Repository, content string) ([]RebaseStep, error) {
+	steps := []RebaseStep{}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		command, rest, _ := strings.Cut(line, " ")
+		if command == "exec" || command == "x" {
+			_, name, found := strings.Cut(rest, "rebase-merge/"+rebaseMessagePrefix)
+			if !found || len(steps) == 0 {
+				continue
+			}
+			name = rebaseMessagePrefix + strings.TrimSuffix(name, ")\"")
+			message, err := readGitDirFile(repo, "rebase-merge/"+name)
+			if err != nil {
+				return nil, err
+			}
+			last := &steps[len(steps)-1]
+			last.NewMessage = message
+			if last.Action == RebasePick {
+				last.Action = RebaseReword
+			}
+			continue
+		}
+
+		action, ok := map[string]string{
+			"p": RebasePick, "pick": RebasePick,
+			"r": RebaseReword, "reword": RebaseReword,
+			"e": RebaseEdit, "edit": RebaseEdit,
+			"s": RebaseSquash, "squash": RebaseSquash,
+			"f": RebaseFixup, "fixup": RebaseFixup,
+			"d": RebaseDrop, "drop": RebaseDrop,
+		}[command]
+		if !ok {
+			// Commands the planner does not produce, such as label or merge
+			continue
+		}
+
+		hash, _, _ := strings.Cut(rest, " ")
+		step, err := rebaseStep(repo, action, hash)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, *step)
+	}
+
+	return steps, nil
+}
+
+// rebaseStep creates a plan step for a commit
+func rebaseStep(repo *git.Repository, action string, revision string) (*RebaseStep, error) {
+	commit, err := resolveCommit(repo, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RebaseStep{
+		Action:  action,
+		Hash:    commit.Hash.String(),
+		Message: commit.Message,
+		Author:  commit.Author.Name,
+		Date:    commit.Author.When,
+	}, nil
+}
+
+// shellQuote quotes a string for use as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
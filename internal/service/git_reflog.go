@@ -0,0 +1,489 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// maxUndoEntries is the number of operations that can be undone per repository
+const maxUndoEntries = 50
+
+// How the working tree is brought back when an operation is undone
+const (
+	RestoreCheckout = "checkout" // Check out the branch or commit HEAD was on
+	RestoreSoft     = "soft"     // Move the branch back, keeping the undone changes staged
+	RestoreMixed    = "mixed"    // Move the branch back, keeping the undone changes in the working tree
+	RestoreKeep     = "keep"     // Move the branch back and update the working tree, keeping local changes
+)
+
+// ReflogEntry is an entry of the reflog of HEAD or a branch
+type ReflogEntry struct {
+	Selector  string    `json:"selector"`  // Reflog selector, e.g. "HEAD@{0}"
+	OldHash   string    `json:"oldHash"`   // Commit the ref pointed to before, zeros when it was created
+	NewHash   string    `json:"newHash"`   // Commit the ref pointed to after
+	Committer string    `json:"committer"` // Who updated the ref
+	Email     string    `json:"email"`
+	Date      time.Time `json:"date"`
+	Action    string    `json:"action"`  // Operation that updated the ref, e.g. "commit", "checkout" or "reset"
+	Message   string    `json:"message"` // Full reflog message
+}
+
+// UndoEntry records the state of HEAD before an operation performed through the service
+type UndoEntry struct {
+	ID          int       `json:"id"`
+	Operation   string    `json:"operation"` // "commit", "checkout", "merge" or "reset"
+	Date        time.Time `json:"date"`
+	Branch      string    `json:"branch"`      // Branch HEAD was on before the operation, empty when detached
+	Hash        string    `json:"hash"`        // Commit HEAD pointed to before the operation
+	AfterBranch string    `json:"afterBranch"` // Branch HEAD was on after the operation
+	AfterHash   string    `json:"afterHash"`   // Commit HEAD pointed to after the operation
+	Restore     string    `json:"restore"`     // How undoing restores the working tree: "checkout", "soft", "mixed" or "keep"
+}
+
+// UndoPreview describes what undoing an operation will change
+type UndoPreview struct {
+	Entry           UndoEntry    `json:"entry"`
+	Description     string       `json:"description"`     // What the undo does, in words
+	CurrentBranch   string       `json:"currentBranch"`   // Branch HEAD is on now, empty when detached
+	CurrentHash     string       `json:"currentHash"`     // Commit HEAD points to now
+	Moved           bool         `json:"moved"`           // HEAD changed again after the operation, those changes are undone too
+	RemovedCommits  []CommitInfo `json:"removedCommits"`  // Commits leaving the branch, they stay reachable from the reflog
+	RestoredCommits []CommitInfo `json:"restoredCommits"` // Commits coming back to the branch
+	Files           []FileDiff   `json:"files"`           // Files that differ between the current and the restored commit, without contents
+}
+
+// refState is the branch HEAD is on and the commit it points to
+type refState struct {
+	branch string
+	hash   plumbing.Hash
+}
+
+// GetReflog returns the reflog of HEAD, when ref is empty, or of a branch, most recent first.
+// A limit of 0 returns every entry.
+func (s *GitService) GetReflog(projectPath string, ref string, limit int) ([]ReflogEntry, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	name := plumbing.HEAD
+	switch {
+	case ref == "" || ref == "HEAD":
+	case strings.HasPrefix(ref, "refs/"):
+		name = plumbing.ReferenceName(ref)
+	default:
+		name = plumbing.NewBranchReferenceName(ref)
+	}
+
+	file, err := reflogPath(repo, name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []ReflogEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read reflog: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	entries := []ReflogEntry{}
+	for i := len(lines) - 1; i >= 0; i-- {
+		entry, ok := parseReflogLine(lines[i])
+		if !ok {
+			continue
+		}
+		entry.Selector = fmt.Sprintf("%s@{%d}", name.Short(), len(entries))
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) >= limit {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// parseReflogLine parses a reflog line: "<old> <new> <name> <<email>> <time> <zone>\t<message>"
+func parseReflogLine(line string) (ReflogEntry, bool) {
+	header, message, _ := strings.Cut(line, "\t")
+	if len(header) < 82 {
+		return ReflogEntry{}, false
+	}
+
+	entry := ReflogEntry{
+		OldHash: header[:40],
+		NewHash: header[41:81],
+		Message: message,
+	}
+
+	identity := header[82:]
+	emailStart := strings.LastIndex(identity, "<")
+	emailEnd := strings.LastIndex(identity, ">")
+	if emailStart < 0 || emailEnd < emailStart {
+		return ReflogEntry{}, false
+	}
+	entry.Committer = strings.TrimSpace(identity[:emailStart])
+	entry.Email = identity[emailStart+1 : emailEnd]
+
+	if fields := strings.Fields(identity[emailEnd+1:]); len(fields) == 2 {
+		if seconds, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			entry.Date = time.Unix(seconds, 0)
+			if zone, err := time.Parse("-0700", fields[1]); err == nil {
+				entry.Date = entry.Date.In(zone.Location())
+			}
+		}
+	}
+
+	entry.Action, _, _ = strings.Cut(message, ": ")
+	return entry, true
+}
+
+// reflogPath returns the reflog file of a ref. HEAD has its own reflog in every
+// working tree, the reflogs of branches are shared.
+func reflogPath(repo *git.Repository, name plumbing.ReferenceName) (string, error) {
+	dir, err := gitDir(repo)
+	if err != nil {
+		return "", err
+	}
+	if name == plumbing.HEAD {
+		return filepath.Join(dir, "logs", "HEAD"), nil
+	}
+	return filepath.Join(commonGitDir(dir), "logs", filepath.FromSlash(name.String())), nil
+}
+
+// appendReflog adds an entry to the reflog of a ref, as git does when it updates
+// the ref. go-git updates refs without writing their reflogs.
+func appendReflog(repo *git.Repository, name plumbing.ReferenceName, oldHash, newHash plumbing.Hash, who object.Signature, message string) error {
+	file, err := reflogPath(repo, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create reflog directory: %w", err)
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open reflog: %w", err)
+	}
+	defer f.Close()
+
+	message = strings.ReplaceAll(strings.TrimSpace(message), "\n", " ")
+	line := fmt.Sprintf("%s %s %s <%s> %d %s\t%s\n", oldHash, newHash, who.Name, who.Email, who.When.Unix(), who.When.Format("-0700"), message)
+	if _, err := f.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write reflog: %w", err)
+	}
+	return nil
+}
+
+// logCommit writes the reflog entries of a commit created with go-git to HEAD and its branch
+func logCommit(repo *git.Repository, before refState, commit *object.Commit, amend bool) error {
+	action := "commit"
+	switch {
+	case amend:
+		action = "commit (amend)"
+	case before.hash.IsZero():
+		action = "commit (initial)"
+	case commit.NumParents() > 1:
+		action = "commit (merge)"
+	}
+	subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	message := action + ": " + subject
+
+	if before.branch != "" {
+		branch := plumbing.NewBranchReferenceName(before.branch)
+		if err := appendReflog(repo, branch, before.hash, commit.Hash, commit.Committer, message); err != nil {
+			return err
+		}
+	}
+	return appendReflog(repo, plumbing.HEAD, before.hash, commit.Hash, commit.Committer, message)
+}
+
+// currentRefState returns the branch HEAD is on and its commit, zero on an unborn branch
+func currentRefState(repo *git.Repository) (refState, error) {
+	var state refState
+
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return state, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if head.Type() == plumbing.SymbolicReference && head.Target().IsBranch() {
+		state.branch = head.Target().Short()
+	}
+
+	resolved, err := repo.Head()
+	switch {
+	case err == plumbing.ErrReferenceNotFound:
+	case err != nil:
+		return state, fmt.Errorf("failed to get HEAD: %w", err)
+	default:
+		state.hash = resolved.Hash()
+	}
+
+	return state, nil
+}
+
+// recordUndo remembers the state HEAD was in before an operation, if the operation
+// changed it, so the operation can be undone. Operations on an unborn branch are
+// not recorded, there is nothing to go back to.
+func (s *GitService) recordUndo(projectPath string, repo *git.Repository, operation string, restore string, before refState) {
+	after, err := currentRefState(repo)
+	if err != nil || before.hash.IsZero() || after == before {
+		return
+	}
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return
+	}
+
+	s.undoLock.Lock()
+	defer s.undoLock.Unlock()
+
+	s.undoSeq++
+	entries := append(s.undo[absPath], UndoEntry{
+		ID:          s.undoSeq,
+		Operation:   operation,
+		Date:        time.Now(),
+		Branch:      before.branch,
+		Hash:        before.hash.String(),
+		AfterBranch: after.branch,
+		AfterHash:   after.hash.String(),
+		Restore:     restore,
+	})
+	if len(entries) > maxUndoEntries {
+		entries = entries[len(entries)-maxUndoEntries:]
+	}
+	s.undo[absPath] = entries
+}
+
+// ListUndoOperations returns the operations that can be undone, most recent first
+func (s *GitService) ListUndoOperations(projectPath string) ([]UndoEntry, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	s.undoLock.Lock()
+	defer s.undoLock.Unlock()
+
+	entries := s.undo[absPath]
+	result := make([]UndoEntry, len(entries))
+	for i, entry := range entries {
+		result[len(entries)-1-i] = entry
+	}
+	return result, nil
+}
+
+// undoEntry returns a recorded operation
+func (s *GitService) undoEntry(projectPath string, id int) (UndoEntry, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return UndoEntry{}, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	s.undoLock.Lock()
+	defer s.undoLock.Unlock()
+	for _, entry := range s.undo[absPath] {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return UndoEntry{}, fmt.Errorf("no operation %d to undo", id)
+}
+
+// PreviewUndo describes what undoing a recorded operation will change
+func (s *GitService) PreviewUndo(projectPath string, id int) (*UndoPreview, error) {
+	entry, err := s.undoEntry(projectPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	current, err := currentRefState(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &UndoPreview{
+		Entry:         entry,
+		CurrentBranch: current.branch,
+		CurrentHash:   current.hash.String(),
+		Moved:         current.branch != entry.AfterBranch || current.hash.String() != entry.AfterHash,
+		Files:         []FileDiff{},
+	}
+
+	target := entry.Hash[:7]
+	if entry.Restore == RestoreCheckout {
+		if entry.Branch != "" {
+			preview.Description = fmt.Sprintf("Check out %s again", entry.Branch)
+		} else {
+			preview.Description = fmt.Sprintf("Check out commit %s again, detaching HEAD", target)
+		}
+	} else {
+		moved := "HEAD"
+		if entry.Branch != "" {
+			moved = entry.Branch
+		}
+		switch entry.Restore {
+		case RestoreSoft:
+			preview.Description = fmt.Sprintf("Move %s back to %s, keeping the undone changes staged", moved, target)
+		case RestoreMixed:
+			preview.Description = fmt.Sprintf("Move %s back to %s, keeping the undone changes in the working tree", moved, target)
+		default:
+			preview.Description = fmt.Sprintf("Move %s back to %s and update the working tree, keeping local changes", moved, target)
+		}
+	}
+
+	// A checkout leaves the branch alone, other operations move it
+	if entry.Restore != RestoreCheckout {
+		if preview.RemovedCommits, err = commitsBetween(repo, projectPath, current.hash.String(), entry.Hash); err != nil {
+			return nil, err
+		}
+		if preview.RestoredCommits, err = commitsBetween(repo, projectPath, entry.Hash, current.hash.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	_, diffs, err := s.compare(repo, CompareOptions{Base: current.hash.String(), Target: entry.Hash}, diffSelection{statsOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	preview.Files = append(preview.Files, diffs...)
+
+	return preview, nil
+}
+
+// UndoOperation restores the state HEAD was in before a recorded operation.
+// The operation and every later one are removed from the undo list.
+func (s *GitService) UndoOperation(projectPath string, id int) error {
+	entry, err := s.undoEntry(projectPath, id)
+	if err != nil {
+		return err
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	current, err := currentRefState(repo)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case entry.Restore == RestoreCheckout && entry.Branch != "":
+		_, err = runGit(projectPath, "checkout", entry.Branch)
+	case entry.Restore == RestoreCheckout:
+		_, err = runGit(projectPath, "checkout", "--detach", entry.Hash)
+	case current.branch != entry.Branch:
+		// Resetting another branch than the one the operation changed would lose its commits
+		if entry.Branch == "" {
+			return errors.New("cannot undo: HEAD was detached during the operation and is now on a branch")
+		}
+		return fmt.Errorf("cannot undo: the operation changed %s, check it out first", entry.Branch)
+	default:
+		_, err = runGit(projectPath, "reset", "--"+entry.Restore, entry.Hash)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to undo %s: %w", entry.Operation, err)
+	}
+
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	s.undoLock.Lock()
+	entries := s.undo[absPath]
+	for i, recorded := range entries {
+		if recorded.ID == id {
+			s.undo[absPath] = entries[:i]
+			break
+		}
+	}
+	s.undoLock.Unlock()
+
+	return nil
+}
+
+// Checkout switches to a branch, or detaches HEAD at any other revision. Local
+// changes are carried over, git refuses the checkout when they would be overwritten.
+func (s *GitService) Checkout(projectPath string, revision string) error {
+	if revision == "" || strings.HasPrefix(revision, "-") {
+		return fmt.Errorf("invalid revision: %q", revision)
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	before, err := currentRefState(repo)
+	if err != nil {
+		return err
+	}
+
+	// Anything but a local branch is checked out detached, git does not guess a new
+	// branch from a remote branch of the same name
+	args := []string{"checkout", revision}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(revision), false); err != nil {
+		args = []string{"checkout", "--detach", revision}
+	}
+	if _, err := runGit(projectPath, args...); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", revision, err)
+	}
+
+	s.recordUndo(projectPath, repo, "checkout", RestoreCheckout, before)
+	return nil
+}
+
+// commitsBetween returns the commits reachable from one revision but not from another, at most 100
+func commitsBetween(repo *git.Repository, projectPath string, from string, exclude string) ([]CommitInfo, error) {
+	commits := []CommitInfo{}
+	if from == plumbing.ZeroHash.String() {
+		return commits, nil
+	}
+
+	args := []string{"rev-list", "--max-count=100", from}
+	if exclude != plumbing.ZeroHash.String() {
+		args = append(args, "^"+exclude)
+	}
+	out, err := runGit(projectPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		commit, err := repo.CommitObject(plumbing.NewHash(strings.TrimSpace(scanner.Text())))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit: %w", err)
+		}
+		parentHashes := make([]string, len(commit.ParentHashes))
+		for i, hash := range commit.ParentHashes {
+			parentHashes[i] = hash.String()
+		}
+		commits = append(commits, CommitInfo{
+			Hash:         commit.Hash.String(),
+			Message:      strings.TrimSpace(commit.Message),
+			Author:       commit.Author.Name,
+			AuthorEmail:  commit.Author.Email,
+			Date:         commit.Author.When,
+			ParentHashes: parentHashes,
+		})
+	}
+
+	return commits, nil
+}
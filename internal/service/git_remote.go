@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// RemoteInfo describes a remote of the repository
+type RemoteInfo struct {
+	Name      string   `json:"name"`
+	FetchURLs []string `json:"fetchUrls"` // URLs fetched from, usually one
+	PushURLs  []string `json:"pushUrls"`  // URLs pushed to, the fetch URLs unless push URLs are configured
+	Fetch     []string `json:"fetch"`     // Fetch refspecs
+}
+
+// BranchUpstream describes the upstream branch a local branch tracks
+type BranchUpstream struct {
+	Branch string `json:"branch"` // Local branch
+	Remote string `json:"remote"` // Remote name, "." for a local upstream branch
+	Merge  string `json:"merge"`  // Full name of the branch on the remote, like "refs/heads/main"
+	Name   string `json:"name"`   // Short name of the upstream, like "origin/main"
+}
+
+// ListRemotes returns the remotes configured in the repository
+func (s *GitService) ListRemotes(projectPath string) ([]RemoteInfo, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	// The raw section keeps push URLs, which the parsed remotes drop
+	remotes := []RemoteInfo{}
+	for _, section := range cfg.Raw.Section("remote").Subsections {
+		remote := RemoteInfo{
+			Name:      section.Name,
+			FetchURLs: nonNil(section.OptionAll("url")),
+			PushURLs:  section.OptionAll("pushurl"),
+			Fetch:     nonNil(section.OptionAll("fetch")),
+		}
+		if len(remote.PushURLs) == 0 {
+			remote.PushURLs = remote.FetchURLs
+		}
+		remotes = append(remotes, remote)
+	}
+	return remotes, nil
+}
+
+// AddRemote adds a remote with the default fetch refspec
+func (s *GitService) AddRemote(projectPath string, name string, url string) error {
+	if url == "" {
+		return errors.New("no remote URL")
+	}
+	if _, err := runGit(projectPath, "remote", "add", "--", name, url); err != nil {
+		return fmt.Errorf("failed to add remote %s: %w", name, err)
+	}
+	return nil
+}
+
+// RenameRemote renames a remote, with its remote-tracking branches and the branches tracking them
+func (s *GitService) RenameRemote(projectPath string, oldName string, newName string) error {
+	if _, err := runGit(projectPath, "remote", "rename", "--", oldName, newName); err != nil {
+		return fmt.Errorf("failed to rename remote %s: %w", oldName, err)
+	}
+	return nil
+}
+
+// RemoveRemote removes a remote, with its remote-tracking branches and the upstream
+// configuration of the branches tracking them
+func (s *GitService) RemoveRemote(projectPath string, name string) error {
+	if _, err := runGit(projectPath, "remote", "remove", "--", name); err != nil {
+		return fmt.Errorf("failed to remove remote %s: %w", name, err)
+	}
+	return nil
+}
+
+// SetRemoteURL sets the URL fetched from, or pushed to when push is set. An empty
+// push URL removes the push URLs, so pushes go to the fetch URL again.
+func (s *GitService) SetRemoteURL(projectPath string, name string, url string, push bool) error {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("failed to read git configuration: %w", err)
+	}
+	if !cfg.Raw.Section("remote").HasSubsection(name) {
+		return fmt.Errorf("remote %s not found", name)
+	}
+
+	switch {
+	case push && url == "":
+		if !cfg.Raw.Section("remote").Subsection(name).HasOption("pushurl") {
+			return nil
+		}
+		_, err = runGit(projectPath, "config", "--unset-all", "remote."+name+".pushurl")
+	case push:
+		_, err = runGit(projectPath, "remote", "set-url", "--push", "--", name, url)
+	case url == "":
+		return errors.New("no remote URL")
+	default:
+		_, err = runGit(projectPath, "remote", "set-url", "--", name, url)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set URL of remote %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetUpstream returns the upstream of a local branch, nil when it has none
+func (s *GitService) GetUpstream(projectPath string, branch string) (*BranchUpstream, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	upstreams, err := branchUpstreams(repo)
+	if err != nil {
+		return nil, err
+	}
+	return upstreams[branch], nil
+}
+
+// SetUpstream makes a local branch track a branch of a remote, or of the repository
+// itself with the "." remote. The remote branch does not need to be fetched yet. An
+// empty remote removes the upstream of the branch.
+func (s *GitService) SetUpstream(projectPath string, branch string, remote string, remoteBranch string) error {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(branch), false); err != nil {
+		return fmt.Errorf("branch %s not found: %w", branch, err)
+	}
+
+	if remote == "" {
+		upstreams, err := branchUpstreams(repo)
+		if err != nil {
+			return err
+		}
+		if upstreams[branch] == nil {
+			return nil
+		}
+		if _, err := runGit(projectPath, "branch", "--unset-upstream", "--", branch); err != nil {
+			return fmt.Errorf("failed to remove upstream of %s: %w", branch, err)
+		}
+		return nil
+	}
+
+	if remote != "." {
+		if _, err := repo.Remote(remote); errors.Is(err, git.ErrRemoteNotFound) {
+			return fmt.Errorf("remote %s not found", remote)
+		} else if err != nil {
+			return fmt.Errorf("failed to get remote %s: %w", remote, err)
+		}
+	}
+	if remoteBranch == "" {
+		return errors.New("no upstream branch")
+	}
+	merge := remoteBranch
+	if !strings.HasPrefix(merge, "refs/") {
+		merge = plumbing.NewBranchReferenceName(remoteBranch).String()
+	}
+
+	// "git branch --set-upstream-to" requires the remote-tracking branch to exist,
+	// so the configuration is written directly
+	if _, err := runGit(projectPath, "config", "branch."+branch+".remote", remote); err != nil {
+		return fmt.Errorf("failed to set upstream of %s: %w", branch, err)
+	}
+	if _, err := runGit(projectPath, "config", "branch."+branch+".merge", merge); err != nil {
+		return fmt.Errorf("failed to set upstream of %s: %w", branch, err)
+	}
+	return nil
+}
+
+// branchUpstreams returns the upstream of the local branches that have one, by branch name
+func branchUpstreams(repo *git.Repository) (map[string]*BranchUpstream, error) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	upstreams := make(map[string]*BranchUpstream)
+	for name, branch := range cfg.Branches {
+		if branch.Remote == "" || branch.Merge == "" {
+			continue
+		}
+		upstream := &BranchUpstream{
+			Branch: name,
+			Remote: branch.Remote,
+			Merge:  branch.Merge.String(),
+			Name:   branch.Remote + "/" + branch.Merge.Short(),
+		}
+		if branch.Remote == "." {
+			upstream.Name = branch.Merge.Short()
+		}
+		upstreams[name] = upstream
+	}
+	return upstreams, nil
+}
+
+// nonNil returns an empty slice instead of nil, so it is sent as an empty array
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// Reset modes
+const (
+	ResetSoft  = "soft"  // Move the branch, keeping the index and the working tree
+	ResetMixed = "mixed" // Move the branch and reset the index, keeping the working tree
+	ResetHard  = "hard"  // Move the branch and reset the index and the working tree
+)
+
+// ResetPreview describes what a reset will change, so it can be confirmed
+type ResetPreview struct {
+	Target         string        `json:"target"`         // Commit the branch will point to
+	Mode           string        `json:"mode"`           // "soft", "mixed" or "hard"
+	Branch         string        `json:"branch"`         // Branch that moves, empty when HEAD is detached
+	RemovedCommits []CommitInfo  `json:"removedCommits"` // Commits leaving the branch, they stay reachable from the reflog
+	LostChanges    []StatusEntry `json:"lostChanges"`    // Uncommitted changes to tracked files a hard reset throws away
+	UnstagedFiles  []StatusEntry `json:"unstagedFiles"`  // Staged changes a mixed reset moves back to the working tree
+}
+
+// RestoreOptions describes where a path is restored from and what is restored
+type RestoreOptions struct {
+	Source   string `json:"source"`   // Revision to restore from, the index when empty
+	Staged   bool   `json:"staged"`   // Restore the index
+	Worktree bool   `json:"worktree"` // Restore the working tree
+}
+
+// PreviewReset returns what resetting the current branch to a revision will change
+func (s *GitService) PreviewReset(projectPath string, revision string, mode string) (*ResetPreview, error) {
+	if err := checkResetMode(mode); err != nil {
+		return nil, err
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	target, err := resolveCommit(repo, revision)
+	if err != nil {
+		return nil, err
+	}
+	current, err := currentRefState(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ResetPreview{
+		Target:        target.Hash.String(),
+		Mode:          mode,
+		Branch:        current.branch,
+		LostChanges:   []StatusEntry{},
+		UnstagedFiles: []StatusEntry{},
+	}
+	if preview.RemovedCommits, err = commitsBetween(repo, projectPath, current.hash.String(), target.Hash.String()); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.status(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if isUntrackedState(entry.Index) {
+			continue
+		}
+		switch {
+		case mode == ResetHard:
+			preview.LostChanges = append(preview.LostChanges, entry)
+		case mode == ResetMixed && (entry.Unmerged || entry.Index != StateUnmodified):
+			preview.UnstagedFiles = append(preview.UnstagedFiles, entry)
+		}
+	}
+
+	return preview, nil
+}
+
+// Reset moves the current branch, or the detached HEAD, to a revision. Mixed
+// resets also reset the index and hard resets the working tree, see PreviewReset
+// for what is lost.
+func (s *GitService) Reset(projectPath string, revision string, mode string) error {
+	if err := checkResetMode(mode); err != nil {
+		return err
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	target, err := resolveCommit(repo, revision)
+	if err != nil {
+		return err
+	}
+	before, err := currentRefState(repo)
+	if err != nil {
+		return err
+	}
+
+	// go-git neither writes the reflog nor handles attributes and filters on reset
+	if _, err := runGit(projectPath, "reset", "--"+mode, target.Hash.String()); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", revision, err)
+	}
+
+	restore := RestoreKeep
+	switch mode {
+	case ResetSoft:
+		restore = RestoreSoft
+	case ResetMixed:
+		restore = RestoreMixed
+	}
+	s.recordUndo(projectPath, repo, "reset", restore, before)
+	return nil
+}
+
+// checkResetMode validates a reset mode
+func checkResetMode(mode string) error {
+	switch mode {
+	case ResetSoft, ResetMixed, ResetHard:
+		return nil
+	default:
+		return fmt.Errorf("invalid reset mode: %q", mode)
+	}
+}
+
+// RestorePath restores a file or directory in the index, the working tree or both,
+// from a revision or from the index. Files under the path that do not exist in the
+// source are removed.
+func (s *GitService) RestorePath(projectPath string, path string, opts RestoreOptions) error {
+	if path == "" {
+		return fmt.Errorf("no path to restore")
+	}
+	if !opts.Staged && !opts.Worktree {
+		opts.Worktree = true
+	}
+	if opts.Staged && opts.Source == "" {
+		// Like git, the index is restored from HEAD when no source is given
+		opts.Source = "HEAD"
+	}
+
+	args := []string{"restore"}
+	if opts.Source != "" {
+		repo, err := s.repository(projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to open repository: %w", err)
+		}
+		commit, err := resolveCommit(repo, opts.Source)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--source="+commit.Hash.String())
+	}
+	if opts.Staged {
+		args = append(args, "--staged")
+	}
+	if opts.Worktree {
+		args = append(args, "--worktree")
+	}
+	args = append(args, "--", filepath.ToSlash(path))
+
+	if _, err := runGit(projectPath, args...); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", path, err)
+	}
+	s.invalidateStatus(projectPath)
+	return nil
+}
+
+// DiscardAllChanges reverts every tracked file of the working tree to its staged
+// version, removing untracked files as well when asked to. Staged changes are kept.
+func (s *GitService) DiscardAllChanges(projectPath string, includeUntracked bool) error {
+	entries, err := s.status(projectPath)
+	if err != nil {
+		return err
+	}
+
+	var tracked bool
+	for _, entry := range entries {
+		if !isUntrackedState(entry.Index) && entry.Worktree != StateUnmodified {
+			tracked = true
+			break
+		}
+	}
+	if tracked {
+		if _, err := runGit(projectPath, "restore", "--worktree", "--", ":/"); err != nil {
+			return fmt.Errorf("failed to discard changes: %w", err)
+		}
+	}
+
+	if includeUntracked {
+		// Ignored files are kept, like build output
+		if _, err := runGit(projectPath, "clean", "-f", "-d", "--", ":/"); err != nil {
+			return fmt.Errorf("failed to remove untracked files: %w", err)
+		}
+	}
+
+	s.invalidateStatus(projectPath)
+	return nil
+}
+
+// DiscardStagedChanges reverts files with staged changes to HEAD in both the index and
+// the working tree, so their unstaged changes are discarded too. Newly added files are
+// deleted. Without files, every file with staged changes is discarded.
+func (s *GitService) DiscardStagedChanges(projectPath string, files []string) error {
+	if len(files) == 0 {
+		entries, err := s.status(projectPath)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !entry.Unmerged && !isUntrackedState(entry.Index) && entry.Index != StateUnmodified {
+				files = append(files, entry.Path)
+				if entry.OrigPath != "" {
+					// The original path of a rename comes back
+					files = append(files, entry.OrigPath)
+				}
+			}
+		}
+		if len(files) == 0 {
+			return nil
+		}
+	}
+
+	args := []string{"restore", "--source=HEAD", "--staged", "--worktree", "--"}
+	for _, file := range files {
+		args = append(args, filepath.ToSlash(file))
+	}
+	if _, err := runGit(projectPath, args...); err != nil {
+		return fmt.Errorf("failed to discard staged changes: %w", err)
+	}
+
+	s.invalidateStatus(projectPath)
+	return nil
+}
+
+// writeWorktreeFile writes a blob to the working tree with the mode it has in git,
+// as a symbolic link for links
+func writeWorktreeFile(fullPath string, content []byte, mode filemode.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if mode == filemode.Symlink {
+		if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to replace file: %w", err)
+		}
+		if err := os.Symlink(string(content), fullPath); err != nil {
+			return fmt.Errorf("failed to create symbolic link: %w", err)
+		}
+		return nil
+	}
+
+	if info, err := os.Lstat(fullPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		// Writing through a link would change its target instead
+		if err := os.Remove(fullPath); err != nil {
+			return fmt.Errorf("failed to replace symbolic link: %w", err)
+		}
+	}
+	if err := os.WriteFile(fullPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	osMode, err := mode.ToOSFileMode()
+	if err != nil {
+		return fmt.Errorf("failed to convert file mode: %w", err)
+	}
+	if err := os.Chmod(fullPath, osMode); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// EventCommitSearchResults is emitted as a commit search finds commits
+const EventCommitSearchResults = "git:commit-search-results"
+
+// Merge commit filters of a commit search
+const (
+	MergesInclude = ""        // Merge commits and regular commits
+	MergesOnly    = "only"    // Merge commits only
+	MergesExclude = "exclude" // Regular commits only
+)
+
+// CommitSearchOptions describes the commits to search for
+type CommitSearchOptions struct {
+	Branch         string    `json:"branch"`         // Revision to search the history of, HEAD when empty
+	AllRefs        bool      `json:"allRefs"`        // Search the history of all branches and tags instead
+	Message        string    `json:"message"`        // Case-insensitive text the message contains
+	Content        string    `json:"content"`        // Text added or removed by the commits
+	ContentRegex   bool      `json:"contentRegex"`   // Content is a regular expression matching added or removed lines (-G), instead of text whose number of occurrences changes (-S)
+	IgnoreCase     bool      `json:"ignoreCase"`     // Match the content case-insensitively
+	Paths          []string  `json:"paths"`          // Globs of the paths the commits touch, like "src/**/*.go"
+	Author         string    `json:"author"`         // Text the author name or email contains
+	Committer      string    `json:"committer"`      // Text the committer name or email contains
+	AuthorSince    time.Time `json:"authorSince"`    // Commits authored at or after this date
+	AuthorUntil    time.Time `json:"authorUntil"`    // Commits authored at or before this date
+	CommitterSince time.Time `json:"committerSince"` // Commits committed at or after this date
+	CommitterUntil time.Time `json:"committerUntil"` // Commits committed at or before this date
+	Merges         string    `json:"merges"`         // One of the Merges constants
+	Limit          int       `json:"limit"`          // Max number of commits to return, all when 0
+}
+
+// CommitSearchResults is the payload of a commit search results event. The HasMore
+// field of the streamed commits is not set, the final event tells whether the limit
+// stopped the search before the end of the history.
+type CommitSearchResults struct {
+	ID      string       `json:"id"`      // Identifier given to SearchCommitHistory
+	Commits []CommitInfo `json:"commits"` // Commits found since the previous event
+	Done    bool         `json:"done"`    // Last event of a search that completed
+	HasMore bool         `json:"hasMore"` // Set on the last event when more commits match than the limit
+}
+
+// searchBatchInterval is how long found commits may wait to be sent together
+const searchBatchInterval = 200 * time.Millisecond
+
+// searchStream sends the commits found by a search as events, the first one right
+// away and the next ones in batches, at most one per interval
+type searchStream struct {
+	s        *GitService
+	id       string
+	lock     sync.Mutex
+	pending  []CommitInfo
+	lastSent time.Time
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newSearchStream starts sending the commits found by a search
+func newSearchStream(s *GitService, id string) *searchStream {
+	stream := &searchStream{s: s, id: id, done: make(chan struct{})}
+	go func() {
+		// Commits found shortly after a batch was sent wait for the next tick, not for the next match
+		ticker := time.NewTicker(searchBatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				stream.lock.Lock()
+				stream.flush()
+				stream.lock.Unlock()
+			case <-stream.done:
+				return
+			}
+		}
+	}()
+	return stream
+}
+
+// add queues a found commit, sent right away unless a batch was just sent
+func (st *searchStream) add(commit CommitInfo) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.pending = append(st.pending, commit)
+	if time.Since(st.lastSent) >= searchBatchInterval {
+		st.flush()
+	}
+}
+
+// flush sends the pending commits, the lock must be held
+func (st *searchStream) flush() {
+	if len(st.pending) == 0 {
+		return
+	}
+	st.s.emit(EventCommitSearchResults, CommitSearchResults{ID: st.id, Commits: st.pending})
+	st.pending = nil
+	st.lastSent = time.Now()
+}
+
+// stop stops sending batches, commits still pending are dropped
+func (st *searchStream) stop() {
+	st.stopOnce.Do(func() { close(st.done) })
+}
+
+// finish stops the stream and sends the final event with the commits still pending
+func (st *searchStream) finish(hasMore bool) {
+	st.stop()
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	commits := st.pending
+	if commits == nil {
+		commits = []CommitInfo{}
+	}
+	st.s.emit(EventCommitSearchResults, CommitSearchResults{ID: st.id, Commits: commits, Done: true, HasMore: hasMore})
+	st.pending = nil
+}
+
+// SearchCommitHistory searches the history for commits matching all the given criteria
+// and returns them, newest first. Commits are also emitted in batches with the given
+// identifier as they are found, and a running search is stopped with CancelCommitSearch.
+func (s *GitService) SearchCommitHistory(projectPath string, id string, opts CommitSearchOptions) ([]CommitInfo, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	tags, err := s.tagsByCommit(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", "-z", "--date-order", "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%cn%x1f%ce%x1f%ct%x1f%B"}
+	if opts.Content != "" {
+		if opts.ContentRegex {
+			args = append(args, "-G"+opts.Content)
+		} else {
+			args = append(args, "-S"+opts.Content)
+		}
+		if opts.IgnoreCase {
+			args = append(args, "--regexp-ignore-case")
+		}
+	}
+	switch opts.Merges {
+	case MergesInclude:
+	case MergesOnly:
+		args = append(args, "--merges")
+	case MergesExclude:
+		args = append(args, "--no-merges")
+	default:
+		return nil, fmt.Errorf("unknown merge filter: %q", opts.Merges)
+	}
+	// Committer dates also let git stop walking early, author dates are filtered below
+	if !opts.CommitterSince.IsZero() {
+		args = append(args, "--since=@"+strconv.FormatInt(opts.CommitterSince.Unix(), 10))
+	}
+	if !opts.CommitterUntil.IsZero() {
+		args = append(args, "--until=@"+strconv.FormatInt(opts.CommitterUntil.Unix(), 10))
+	}
+	if opts.AllRefs {
+		args = append(args, "--all")
+	} else {
+		branch := opts.Branch
+		if branch == "" {
+			branch = "HEAD"
+		}
+		// Resolving first keeps revisions from being taken for options
+		commit, err := resolveCommit(repo, branch)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, commit.Hash.String())
+	}
+	args = append(args, "--")
+	for _, path := range opts.Paths {
+		if path != "" {
+			args = append(args, ":(glob)"+path)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.searchesLock.Lock()
+	if _, running := s.searches[id]; running {
+		s.searchesLock.Unlock()
+		cancel()
+		return nil, fmt.Errorf("commit search %s is already running", id)
+	}
+	s.searches[id] = cancel
+	s.searchesLock.Unlock()
+	defer func() {
+		s.searchesLock.Lock()
+		delete(s.searches, id)
+		s.searchesLock.Unlock()
+		cancel()
+	}()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = projectPath
+	cmd.Env = append(os.Environ(), "LC_ALL=C", "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start commit search: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start commit search: %w", err)
+	}
+
+	commits := []CommitInfo{}
+	stream := newSearchStream(s, id)
+	defer stream.stop()
+
+	hasMore := false
+	reader := bufio.NewReader(stdout)
+	for {
+		record, err := reader.ReadString(0)
+		if err != nil && err != io.EOF {
+			break
+		}
+		record = strings.TrimSuffix(record, "\x00")
+		if record != "" {
+			if commit, ok := parseSearchRecord(record, opts); ok {
+				if opts.Limit > 0 && len(commits) == opts.Limit {
+					hasMore = true
+					break
+				}
+				commit.Tags = tags[plumbing.NewHash(commit.Hash)]
+				commits = append(commits, commit)
+				stream.add(commit)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if hasMore {
+		// Enough commits were found, the rest of the history is not needed
+		cancel()
+	}
+	waitErr := cmd.Wait()
+	if ctx.Err() != nil && !hasMore {
+		return nil, errors.New("commit search cancelled")
+	}
+	if waitErr != nil && !hasMore {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = waitErr.Error()
+		}
+		return nil, fmt.Errorf("failed to search commits: git log failed: %s", msg)
+	}
+
+	for i := range commits {
+		commits[i].HasMore = i < len(commits)-1 || hasMore
+	}
+	stream.finish(hasMore)
+	return commits, nil
+}
+
+// CancelCommitSearch stops a running commit search
+func (s *GitService) CancelCommitSearch(id string) error {
+	s.searchesLock.Lock()
+	cancel, ok := s.searches[id]
+	s.searchesLock.Unlock()
+	if !ok {
+		return fmt.Errorf("no commit search %s in progress", id)
+	}
+	cancel()
+	return nil
+}
+
+// parseSearchRecord turns a record printed by the commit search into a commit, and
+// tells whether it matches the criteria git does not filter on
+func parseSearchRecord(record string, opts CommitSearchOptions) (CommitInfo, bool) {
+	fields := strings.SplitN(record, "\x1f", 9)
+	if len(fields) < 9 {
+		return CommitInfo{}, false
+	}
+	message := fields[8]
+	authorName, authorEmail := fields[2], fields[3]
+	committerName, committerEmail := fields[5], fields[6]
+	authorTime, _ := strconv.ParseInt(fields[4], 10, 64)
+	authorDate := time.Unix(authorTime, 0)
+
+	if !opts.AuthorSince.IsZero() && authorDate.Before(opts.AuthorSince) {
+		return CommitInfo{}, false
+	}
+	if !opts.AuthorUntil.IsZero() && authorDate.After(opts.AuthorUntil) {
+		return CommitInfo{}, false
+	}
+	if opts.Author != "" && !strings.Contains(authorName, opts.Author) && !strings.Contains(authorEmail, opts.Author) {
+		return CommitInfo{}, false
+	}
+	if opts.Committer != "" && !strings.Contains(committerName, opts.Committer) && !strings.Contains(committerEmail, opts.Committer) {
+		return CommitInfo{}, false
+	}
+	if opts.Message != "" && !strings.Contains(strings.ToLower(message), strings.ToLower(opts.Message)) {
+		return CommitInfo{}, false
+	}
+
+	parentHashes := []string{}
+	if fields[1] != "" {
+		parentHashes = strings.Split(fields[1], " ")
+	}
+	return CommitInfo{
+		Hash:         fields[0],
+		Message:      strings.TrimSpace(message),
+		Author:       authorName,
+		AuthorEmail:  authorEmail,
+		Date:         authorDate,
+		ParentHashes: parentHashes,
+	}, true
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// StashEntry represents a single entry of the stash list
+type StashEntry struct {
+	Index   int       `json:"index"`   // Position in the stash list (0 is the most recent)
+	Ref     string    `json:"ref"`     // Reflog selector, e.g. "stash@{0}"
+	Hash    string    `json:"hash"`    // Hash of the stash commit
+	Message string    `json:"message"` // Stash message
+	Branch  string    `json:"branch"`  // Branch the stash was created on
+	Date    time.Time `json:"date"`    // When the stash was created
+}
+
+// StashOptions contains options for saving a stash
+type StashOptions struct {
+	Message          string `json:"message"`          // Optional stash message
+	IncludeUntracked bool   `json:"includeUntracked"` // Also stash untracked files
+	KeepIndex        bool   `json:"keepIndex"`        // Leave staged changes in the index
+}
+
+// ListStashes returns the stash entries of the repository, most recent first
+func (s *GitService) ListStashes(projectPath string) ([]StashEntry, error) {
+	// go-git has no reflog support, so the stash list is read through the git CLI
+	out, err := runGit(projectPath, "stash", "list", "--format=%gd%x00%H%x00%ct%x00%gs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+
+	stashes := []StashEntry{}
+	for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		entry := StashEntry{
+			Index: i,
+			Ref:   fields[0],
+			Hash:  fields[1],
+		}
+		if ts, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			entry.Date = time.Unix(ts, 0)
+		}
+		entry.Branch, entry.Message = parseStashSubject(fields[3])
+
+		stashes = append(stashes, entry)
+	}
+
+	return stashes, nil
+}
+
+// parseStashSubject splits a stash reflog subject ("On main: message" or
+// "WIP on main: abc1234 commit subject") into the branch name and message
+func parseStashSubject(subject string) (string, string) {
+	rest := subject
+	if strings.HasPrefix(rest, "WIP on ") {
+		rest = strings.TrimPrefix(rest, "WIP on ")
+	} else if strings.HasPrefix(rest, "On ") {
+		rest = strings.TrimPrefix(rest, "On ")
+	} else {
+		return "", subject
+	}
+
+	branch, message, found := strings.Cut(rest, ": ")
+	if !found {
+		return "", subject
+	}
+	return branch, message
+}
+
+// SaveStash stashes the local changes of the repository
+func (s *GitService) SaveStash(projectPath string, opts StashOptions) error {
+	args := []string{"stash", "push"}
+	if opts.Message != "" {
+		args = append(args, "--message", opts.Message)
+	}
+	if opts.IncludeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if opts.KeepIndex {
+		args = append(args, "--keep-index")
+	}
+
+	out, err := runGit(projectPath, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save stash: %w", err)
+	}
+
+	// git exits successfully even when there was nothing to stash
+	if strings.Contains(out, "No local changes to save") {
+		return fmt.Errorf("no local changes to stash")
+	}
+
+	return nil
+}
+
+// ApplyStash applies a stash entry to the working tree, keeping it in the stash list
+func (s *GitService) ApplyStash(projectPath string, index int) error {
+	if _, err := runGit(projectPath, "stash", "apply", stashRef(index)); err != nil {
+		return fmt.Errorf("failed to apply stash: %w", err)
+	}
+	return nil
+}
+
+// PopStash applies a stash entry to the working tree and removes it from the stash list
+func (s *GitService) PopStash(projectPath string, index int) error {
+	if _, err := runGit(projectPath, "stash", "pop", stashRef(index)); err != nil {
+		return fmt.Errorf("failed to pop stash: %w", err)
+	}
+	return nil
+}
+
+// DropStash removes a stash entry from the stash list
+func (s *GitService) DropStash(projectPath string, index int) error {
+	if _, err := runGit(projectPath, "stash", "drop", stashRef(index)); err != nil {
+		return fmt.Errorf("failed to drop stash: %w", err)
+	}
+	return nil
+}
+
+// GetStashDiff returns the per-file diffs recorded in a stash entry,
+// including untracked files when the stash was saved with them
+func (s *GitService) GetStashDiff(projectPath string, index int) ([]FileDiff, error) {
+	out, err := runGit(projectPath, "rev-parse", "--verify", "--quiet", stashRef(index))
+	if err != nil {
+		return nil, fmt.Errorf("stash entry %d not found", index)
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	// A stash commit has the base commit as first parent, the index state as second
+	// parent and, when untracked files were included, their tree as third parent
+	stash, err := repo.CommitObject(plumbing.NewHash(strings.TrimSpace(out)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stash commit: %w", err)
+	}
+	if stash.NumParents() < 2 {
+		return nil, fmt.Errorf("invalid stash commit %s", stash.Hash)
+	}
+
+	base, err := stash.Parent(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stash base commit: %w", err)
+	}
+	baseTree, err := base.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+	stashTree, err := stash.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	diffs, err := s.diffTrees(repo, baseTree, stashTree)
+	if err != nil {
+		return nil, err
+	}
+
+	if stash.NumParents() > 2 {
+		untracked, err := stash.Parent(2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get untracked files commit: %w", err)
+		}
+		untrackedTree, err := untracked.Tree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tree: %w", err)
+		}
+
+		untrackedDiffs, err := s.diffTrees(repo, nil, untrackedTree)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, untrackedDiffs...)
+	}
+
+	return diffs, nil
+}
+
+// stashRef returns the reflog selector for a stash index
+func stashRef(index int) string {
+	return fmt.Sprintf("stash@{%d}", index)
+}
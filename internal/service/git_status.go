@@ -0,0 +1,674 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// File states of a status entry, the letters used by "git status --porcelain=v2"
+const (
+	StateUnmodified  = "."
+	StateModified    = "M"
+	StateTypeChanged = "T" // Changed between regular file, symlink and submodule
+	StateAdded       = "A"
+	StateDeleted     = "D"
+	StateRenamed     = "R"
+	StateCopied      = "C"
+	StateUnmerged    = "U"
+	StateUntracked   = "?"
+	StateIgnored     = "!"
+)
+
+// renameScore is the similarity, in percent, from which a deleted and an added file are a rename, like git
+const renameScore = 50
+
+// StatusEntry is the status of a path, with the state of the index against HEAD
+// and of the working tree against the index reported separately
+type StatusEntry struct {
+	Path         string          `json:"path"`               // Path relative to the repository root
+	OrigPath     string          `json:"origPath,omitempty"` // Path in HEAD of a renamed or copied file
+	Index        string          `json:"index"`              // State of the index against HEAD
+	Worktree     string          `json:"worktree"`           // State of the working tree against the index
+	Unmerged     bool            `json:"unmerged"`           // The path has conflicts, Index and Worktree then tell what each side did, as in "UU" or "AA"
+	Score        int             `json:"score,omitempty"`    // Similarity of a renamed or copied file in percent, when the backend reports it
+	HeadMode     string          `json:"headMode,omitempty"` // Octal file modes, "000000" when the path is absent
+	IndexMode    string          `json:"indexMode,omitempty"`
+	WorktreeMode string          `json:"worktreeMode,omitempty"`
+	HeadHash     string          `json:"headHash,omitempty"`
+	IndexHash    string          `json:"indexHash,omitempty"`
+	Submodule    *SubmoduleState `json:"submodule,omitempty"` // Set when the path is a submodule
+}
+
+// SubmoduleState describes the changes of a submodule
+type SubmoduleState struct {
+	CommitChanged bool `json:"commitChanged"` // The checked-out commit differs from the recorded one
+	Modified      bool `json:"modified"`      // The submodule has changes to tracked files
+	Untracked     bool `json:"untracked"`     // The submodule has untracked files
+}
+
+// StatusOptions contains options for computing the status
+type StatusOptions struct {
+	Ignored bool `json:"ignored"` // Also report ignored files
+}
+
+// GetStatusEntries returns the status of every changed path of the repository
+func (s *GitService) GetStatusEntries(projectPath string, opts StatusOptions) ([]StatusEntry, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	// Ignored files are rarely asked for and can be many, they are not cached
+	if opts.Ignored {
+		entries, err := s.backend(OpStatus).Status(absPath, opts)
+		if err != nil {
+			return nil, err
+		}
+		sortStatusEntries(entries)
+		return entries, nil
+	}
+	return s.status(absPath)
+}
+
+// fileStatuses flattens status entries into one file status per staged and unstaged change
+func fileStatuses(entries []StatusEntry) []FileStatus {
+	files := []FileStatus{}
+	for _, entry := range entries {
+		submodule := entry.Submodule != nil
+		switch {
+		case entry.Unmerged:
+			files = append(files, FileStatus{File: entry.Path, Staged: false, Status: StatusConflicted})
+		case isUntrackedState(entry.Index):
+			files = append(files, FileStatus{File: entry.Path, Staged: false, Status: entry.Index})
+		default:
+			if entry.Index != StateUnmodified {
+				files = append(files, FileStatus{File: entry.Path, Staged: true, Status: entry.Index, OrigPath: entry.OrigPath, Submodule: submodule})
+			}
+			if entry.Worktree != StateUnmodified {
+				files = append(files, FileStatus{File: entry.Path, Staged: false, Status: entry.Worktree, Submodule: submodule})
+			}
+		}
+	}
+	return files
+}
+
+// statusWalk compares the working tree with the index
+type statusWalk struct {
+	root        string
+	opts        StatusOptions
+	indexed     map[string]*index.Entry
+	indexedDirs map[string]bool // Directories containing tracked files
+	unmerged    map[string]bool // Files with conflicts, reported from their index stages only
+	indexTime   time.Time       // Modification time of the index file
+	seen        map[string]bool // Tracked files found in the working tree
+	entries     map[string]*StatusEntry
+	untracked   []StatusEntry // Untracked and ignored files, kept apart as a file deleted from the index can be both
+}
+
+// repositoryStatus computes the status of a repository. Like git, files whose size
+// and modification time match their index entry are taken as unchanged, so only
+// files touched since they were staged are read and hashed.
+func repositoryStatus(repo *git.Repository, opts StatusOptions) ([]StatusEntry, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+
+	dir, err := gitDir(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	walk := &statusWalk{
+		root:        worktree.Filesystem.Root(),
+		opts:        opts,
+		indexed:     make(map[string]*index.Entry, len(idx.Entries)),
+		indexedDirs: trackedDirs(idx),
+		unmerged:    make(map[string]bool),
+		seen:        make(map[string]bool, len(idx.Entries)),
+		entries:     make(map[string]*StatusEntry),
+	}
+	// Files changed in the same instant the index was written may have changed
+	// without their modification time showing it, those are always hashed
+	if info, err := os.Stat(filepath.Join(dir, "index")); err == nil {
+		walk.indexTime = info.ModTime()
+	}
+
+	// Unmerged paths have one index entry per side of the merge instead of a merged one
+	stages := make(map[string][]index.Stage)
+	for _, entry := range idx.Entries {
+		if entry.Stage != 0 {
+			stages[entry.Name] = append(stages[entry.Name], entry.Stage)
+			walk.unmerged[entry.Name] = true
+		}
+	}
+	for _, entry := range idx.Entries {
+		if entry.Stage == 0 && !walk.unmerged[entry.Name] {
+			walk.indexed[entry.Name] = entry
+		}
+	}
+
+	if err := walk.diffIndex(repo); err != nil {
+		return nil, err
+	}
+
+	excludes, err := excludePatterns(repo)
+	if err != nil {
+		return nil, err
+	}
+	if err := walk.walk("", append(excludes, worktree.Excludes...)); err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	for name, entry := range walk.indexed {
+		if !walk.seen[name] {
+			status := walk.entry(name, entry)
+			status.Worktree = StateDeleted
+			status.WorktreeMode = fileModeString(filemode.Empty)
+		}
+	}
+
+	for name, nameStages := range stages {
+		x, y := unmergedState(nameStages)
+		walk.entries[name] = &StatusEntry{Path: name, Index: x, Worktree: y, Unmerged: true}
+	}
+
+	entries := make([]StatusEntry, 0, len(walk.entries)+len(walk.untracked))
+	for _, entry := range walk.entries {
+		entries = append(entries, *entry)
+	}
+	entries = append(entries, walk.untracked...)
+	sortStatusEntries(entries)
+	return entries, nil
+}
+
+// diffIndex records the changes of the index against HEAD. The index is turned
+// into tree objects, in memory, so that unchanged directories are skipped by hash
+// and renames are detected like between two commits.
+func (w *statusWalk) diffIndex(repo *git.Repository) error {
+	var headTree *object.Tree
+	head, err := repo.Head()
+	switch {
+	case err == plumbing.ErrReferenceNotFound:
+		// No commit yet, everything in the index is added
+	case err != nil:
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	default:
+		commit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to get commit: %w", err)
+		}
+		if headTree, err = commit.Tree(); err != nil {
+			return fmt.Errorf("failed to get tree: %w", err)
+		}
+	}
+
+	entries := make([]*index.Entry, 0, len(w.indexed))
+	for _, entry := range w.indexed {
+		entries = append(entries, entry)
+	}
+	indexTree, err := buildIndexTree(repo.Storer, entries)
+	if err != nil {
+		return err
+	}
+
+	changes, err := object.DiffTreeWithOptions(context.Background(), headTree, indexTree, &object.DiffTreeOptions{
+		DetectRenames: true,
+		RenameScore:   renameScore,
+		RenameLimit:   1000,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compare the index with HEAD: %w", err)
+	}
+
+	for _, change := range changes {
+		action, err := change.Action()
+		if err != nil {
+			return err
+		}
+
+		switch action {
+		case merkletrie.Insert:
+			status := w.entry(change.To.Name, w.indexed[change.To.Name])
+			status.Index = StateAdded
+			status.HeadMode = fileModeString(filemode.Empty)
+			status.HeadHash = plumbing.ZeroHash.String()
+		case merkletrie.Delete:
+			if w.unmerged[change.From.Name] {
+				continue
+			}
+			status := w.headOnlyEntry(change.From)
+			status.Index = StateDeleted
+		case merkletrie.Modify:
+			status := w.entry(change.To.Name, w.indexed[change.To.Name])
+			status.HeadMode = fileModeString(change.From.TreeEntry.Mode)
+			status.HeadHash = change.From.TreeEntry.Hash.String()
+			switch {
+			case change.From.Name != change.To.Name:
+				status.Index = StateRenamed
+				status.OrigPath = change.From.Name
+				if change.From.TreeEntry.Hash == change.To.TreeEntry.Hash {
+					status.Score = 100
+				}
+			case fileType(change.From.TreeEntry.Mode) != fileType(change.To.TreeEntry.Mode):
+				status.Index = StateTypeChanged
+			default:
+				status.Index = StateModified
+			}
+			if change.From.TreeEntry.Mode == filemode.Submodule && status.Submodule == nil {
+				status.Submodule = &SubmoduleState{}
+			}
+		}
+	}
+
+	return nil
+}
+
+// entry returns the status entry of a tracked path, creating it unchanged
+func (w *statusWalk) entry(name string, indexEntry *index.Entry) *StatusEntry {
+	if status, ok := w.entries[name]; ok {
+		return status
+	}
+
+	status := &StatusEntry{
+		Path:         name,
+		Index:        StateUnmodified,
+		Worktree:     StateUnmodified,
+		HeadMode:     fileModeString(indexEntry.Mode),
+		IndexMode:    fileModeString(indexEntry.Mode),
+		WorktreeMode: fileModeString(indexEntry.Mode),
+		HeadHash:     indexEntry.Hash.String(),
+		IndexHash:    indexEntry.Hash.String(),
+	}
+	if indexEntry.Mode == filemode.Submodule {
+		status.Submodule = &SubmoduleState{}
+	}
+	w.entries[name] = status
+	return status
+}
+
+// headOnlyEntry returns the status entry of a path that is only in HEAD
+func (w *statusWalk) headOnlyEntry(from object.ChangeEntry) *StatusEntry {
+	status := &StatusEntry{
+		Path:         from.Name,
+		Index:        StateUnmodified,
+		Worktree:     StateUnmodified,
+		HeadMode:     fileModeString(from.TreeEntry.Mode),
+		IndexMode:    fileModeString(filemode.Empty),
+		WorktreeMode: fileModeString(filemode.Empty),
+		HeadHash:     from.TreeEntry.Hash.String(),
+		IndexHash:    plumbing.ZeroHash.String(),
+	}
+	if from.TreeEntry.Mode == filemode.Submodule {
+		status.Submodule = &SubmoduleState{}
+	}
+	w.entries[from.Name] = status
+	return status
+}
+
+// walk compares the files of a working tree directory with their index entries
+func (w *statusWalk) walk(dir string, patterns []gitignore.Pattern) error {
+	dirEntries, err := os.ReadDir(filepath.Join(w.root, filepath.FromSlash(dir)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	patterns, err = appendIgnorePatterns(patterns, w.root, dir)
+	if err != nil {
+		return err
+	}
+	matcher := gitignore.NewMatcher(patterns)
+
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		rel := path.Join(dir, name)
+		if name == ".git" || w.unmerged[rel] {
+			continue
+		}
+
+		entry, tracked := w.indexed[rel]
+		if tracked && entry.Mode == filemode.Submodule {
+			w.seen[rel] = true
+			state, err := submoduleState(filepath.Join(w.root, filepath.FromSlash(rel)), entry.Hash)
+			if err != nil {
+				return err
+			}
+			if state.CommitChanged || state.Modified || state.Untracked {
+				status := w.entry(rel, entry)
+				status.Worktree = StateModified
+				status.Submodule = state
+			}
+			continue
+		}
+
+		if dirEntry.IsDir() {
+			if !w.indexedDirs[rel] && matcher.Match(strings.Split(rel, "/"), true) {
+				if w.opts.Ignored {
+					if err := w.addIgnored(rel); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := w.walk(rel, patterns); err != nil {
+				return err
+			}
+			continue
+		}
+		if !dirEntry.Type().IsRegular() && dirEntry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		if !tracked {
+			state := StateUntracked
+			if matcher.Match(strings.Split(rel, "/"), false) {
+				if !w.opts.Ignored {
+					continue
+				}
+				state = StateIgnored
+			}
+			w.untracked = append(w.untracked, StatusEntry{Path: rel, Index: state, Worktree: state})
+			continue
+		}
+
+		w.seen[rel] = true
+		info, err := dirEntry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Removed while walking
+				delete(w.seen, rel)
+				continue
+			}
+			return err
+		}
+		state, mode, err := w.compare(rel, info, entry)
+		if err != nil {
+			return err
+		}
+		if state != StateUnmodified {
+			status := w.entry(rel, entry)
+			status.Worktree = state
+			status.WorktreeMode = fileModeString(mode)
+		}
+	}
+
+	return nil
+}
+
+// addIgnored records every file below an ignored directory as ignored
+func (w *statusWalk) addIgnored(dir string) error {
+	return filepath.WalkDir(filepath.Join(w.root, filepath.FromSlash(dir)), func(fullPath string, dirEntry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if dirEntry.IsDir() {
+			if dirEntry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(w.root, fullPath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		w.untracked = append(w.untracked, StatusEntry{Path: rel, Index: StateIgnored, Worktree: StateIgnored})
+		return nil
+	})
+}
+
+// compare returns the state of a tracked file against its index entry, and the file mode
+func (w *statusWalk) compare(rel string, info os.FileInfo, entry *index.Entry) (string, filemode.FileMode, error) {
+	mode, err := filemode.NewFromOSFileMode(info.Mode())
+	if err != nil {
+		return StateModified, entry.Mode, nil
+	}
+	if runtime.GOOS == "windows" && mode.IsRegular() && entry.Mode.IsRegular() {
+		// The executable bit cannot be represented on Windows
+		mode = entry.Mode
+	}
+	if fileType(mode) != fileType(entry.Mode) {
+		return StateTypeChanged, mode, nil
+	}
+	if mode != entry.Mode {
+		return StateModified, mode, nil
+	}
+
+	if uint32(info.Size()) == entry.Size && info.ModTime().Equal(entry.ModifiedAt) && entry.ModifiedAt.Before(w.indexTime) {
+		return StateUnmodified, mode, nil
+	}
+
+	hash, err := worktreeFileHash(filepath.Join(w.root, filepath.FromSlash(rel)), info)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return StateDeleted, filemode.Empty, nil
+		}
+		return "", mode, err
+	}
+	if hash != entry.Hash {
+		return StateModified, mode, nil
+	}
+	return StateUnmodified, mode, nil
+}
+
+// worktreeFileHash returns the blob hash of a working tree file, or of the target of a symlink
+func worktreeFileHash(fullPath string, info os.FileInfo) (plumbing.Hash, error) {
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(fullPath)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return plumbing.ComputeHash(plumbing.BlobObject, []byte(filepath.ToSlash(target))), nil
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	defer file.Close()
+
+	hasher := plumbing.NewHasher(plumbing.BlobObject, info.Size())
+	if _, err := io.Copy(hasher, file); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return hasher.Sum(), nil
+}
+
+// submoduleState returns the changes of a submodule against the commit recorded for it.
+// Submodules that are not checked out are unchanged.
+func submoduleState(fullPath string, recorded plumbing.Hash) (*SubmoduleState, error) {
+	state := &SubmoduleState{}
+
+	repo, err := openRepository(fullPath)
+	if err != nil {
+		return state, nil
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return state, nil
+	}
+	state.CommitChanged = head.Hash() != recorded
+
+	entries, err := repositoryStatus(repo, StatusOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status of submodule %s: %w", fullPath, err)
+	}
+	for _, entry := range entries {
+		if entry.Index == StateUntracked {
+			state.Untracked = true
+		} else {
+			state.Modified = true
+		}
+	}
+
+	return state, nil
+}
+
+// unmergedState returns the two letters git uses for a conflict, from the index stages present:
+// 1 is the common ancestor, 2 our side and 3 their side
+func unmergedState(stages []index.Stage) (string, string) {
+	var present [4]bool
+	for _, stage := range stages {
+		if stage >= 1 && stage <= 3 {
+			present[stage] = true
+		}
+	}
+
+	switch {
+	case present[1] && present[2] && present[3]:
+		return StateUnmerged, StateUnmerged // Both modified
+	case present[2] && present[3]:
+		return StateAdded, StateAdded // Both added
+	case present[1] && present[2]:
+		return StateUnmerged, StateDeleted // Deleted by them
+	case present[1] && present[3]:
+		return StateDeleted, StateUnmerged // Deleted by us
+	case present[1]:
+		return StateDeleted, StateDeleted // Both deleted
+	case present[2]:
+		return StateAdded, StateUnmerged // Added by us
+	default:
+		return StateUnmerged, StateAdded // Added by them
+	}
+}
+
+// buildIndexTree builds the tree objects of the merged index entries in memory
+func buildIndexTree(base storer.EncodedObjectStorer, entries []*index.Entry) (*object.Tree, error) {
+	store := &overlayStorer{EncodedObjectStorer: base, objects: make(map[plumbing.Hash]plumbing.EncodedObject)}
+
+	type dirNode struct {
+		files []object.TreeEntry
+		dirs  map[string]*dirNode
+	}
+	newDir := func() *dirNode {
+		return &dirNode{dirs: make(map[string]*dirNode)}
+	}
+
+	root := newDir()
+	for _, entry := range entries {
+		node := root
+		parts := strings.Split(entry.Name, "/")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node.dirs[part]
+			if !ok {
+				child = newDir()
+				node.dirs[part] = child
+			}
+			node = child
+		}
+		node.files = append(node.files, object.TreeEntry{Name: parts[len(parts)-1], Mode: entry.Mode, Hash: entry.Hash})
+	}
+
+	var encode func(node *dirNode) (plumbing.Hash, error)
+	encode = func(node *dirNode) (plumbing.Hash, error) {
+		tree := &object.Tree{Entries: node.files}
+		for name, child := range node.dirs {
+			hash, err := encode(child)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+			tree.Entries = append(tree.Entries, object.TreeEntry{Name: name, Mode: filemode.Dir, Hash: hash})
+		}
+		sort.Sort(object.TreeEntrySorter(tree.Entries))
+
+		obj := &plumbing.MemoryObject{}
+		if err := tree.Encode(obj); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to encode index tree: %w", err)
+		}
+		store.objects[obj.Hash()] = obj
+		return obj.Hash(), nil
+	}
+
+	hash, err := encode(root)
+	if err != nil {
+		return nil, err
+	}
+	return object.GetTree(store, hash)
+}
+
+// overlayStorer serves objects built in memory on top of the repository objects
+type overlayStorer struct {
+	storer.EncodedObjectStorer
+	objects map[plumbing.Hash]plumbing.EncodedObject
+}
+
+// EncodedObject returns an in-memory object, or else the repository object
+func (s *overlayStorer) EncodedObject(objectType plumbing.ObjectType, hash plumbing.Hash) (plumbing.EncodedObject, error) {
+	if obj, ok := s.objects[hash]; ok && (objectType == plumbing.AnyObject || objectType == obj.Type()) {
+		return obj, nil
+	}
+	return s.EncodedObjectStorer.EncodedObject(objectType, hash)
+}
+
+// fileType groups file modes into the kinds git reports type changes between
+func fileType(mode filemode.FileMode) string {
+	switch mode {
+	case filemode.Symlink:
+		return "symlink"
+	case filemode.Submodule:
+		return "submodule"
+	default:
+		return "file"
+	}
+}
+
+// fileModeString formats a file mode like git does in its status
+func fileModeString(mode filemode.FileMode) string {
+	return fmt.Sprintf("%06o", uint32(mode))
+}
+
+// commonGitDir returns the git directory shared by all working trees of a repository.
+// Linked working trees point to it with a commondir file.
+func commonGitDir(dir string) string {
+	content, err := os.ReadFile(filepath.Join(dir, "commondir"))
+	if err != nil {
+		return dir
+	}
+	common := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(dir, common)
+	}
+	return filepath.Clean(common)
+}
+
+// sortStatusEntries orders status entries by path, tracked before untracked entries of the same path
+func sortStatusEntries(entries []StatusEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Path != entries[j].Path {
+			return entries[i].Path < entries[j].Path
+		}
+		return !isUntrackedState(entries[i].Index) && isUntrackedState(entries[j].Index)
+	})
+}
+
+// isUntrackedState reports whether a state is the one of an untracked or ignored file
+func isUntrackedState(state string) bool {
+	return state == StateUntracked || state == StateIgnored
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SubmoduleInfo describes a submodule of the repository
+type SubmoduleInfo struct {
+	Name           string `json:"name"`
+	Path           string `json:"path"` // Path relative to the repository root
+	URL            string `json:"url"`
+	Branch         string `json:"branch"`         // Branch followed by remote updates, if configured
+	RecordedCommit string `json:"recordedCommit"` // Commit recorded in the superproject index
+	CurrentCommit  string `json:"currentCommit"`  // Commit checked out in the submodule, empty when it is not checked out
+	Initialized    bool   `json:"initialized"`    // Whether the submodule is registered in the repository configuration
+	OutOfSync      bool   `json:"outOfSync"`      // The checked-out commit differs from the recorded one
+	Dirty          bool   `json:"dirty"`          // The submodule has uncommitted changes
+}
+
+// SubmoduleUpdateOptions contains options for updating submodules
+type SubmoduleUpdateOptions struct {
+	Paths     []string `json:"paths"`     // Submodules to update, all when empty
+	Init      bool     `json:"init"`      // Initialize submodules that are not initialized yet
+	Recursive bool     `json:"recursive"` // Also update nested submodules
+	Remote    bool     `json:"remote"`    // Check out the latest commit of the tracked branch instead of the recorded one
+}
+
+// ListSubmodules returns the submodules of the repository with their checkout state
+func (s *GitService) ListSubmodules(projectPath string) ([]SubmoduleInfo, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submodules: %w", err)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	infos := []SubmoduleInfo{}
+	for _, submodule := range submodules {
+		subConfig := submodule.Config()
+		info := SubmoduleInfo{
+			Name:   subConfig.Name,
+			Path:   subConfig.Path,
+			URL:    subConfig.URL,
+			Branch: subConfig.Branch,
+		}
+		if registered, ok := cfg.Submodules[subConfig.Name]; ok {
+			info.Initialized = true
+			if registered.URL != "" {
+				info.URL = registered.URL
+			}
+		}
+
+		// An initialized submodule that was never checked out has no repository yet
+		if status, err := submodule.Status(); err == nil {
+			if !status.Expected.IsZero() {
+				info.RecordedCommit = status.Expected.String()
+			}
+			if !status.Current.IsZero() {
+				info.CurrentCommit = status.Current.String()
+			}
+		}
+
+		// The repository of a submodule can outlive its checkout, e.g. after deinit
+		subPath := filepath.Join(projectPath, subConfig.Path)
+		if _, err := os.Stat(filepath.Join(subPath, ".git")); err != nil {
+			info.CurrentCommit = ""
+		}
+
+		if info.CurrentCommit != "" {
+			info.OutOfSync = info.CurrentCommit != info.RecordedCommit
+			entries, err := s.backend(OpStatus).Status(subPath, StatusOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get status of submodule %s: %w", subConfig.Name, err)
+			}
+			info.Dirty = len(entries) > 0
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Path < infos[j].Path
+	})
+
+	return infos, nil
+}
+
+// InitSubmodules registers submodules in the repository configuration so they can be updated.
+// With no paths every submodule is initialized.
+func (s *GitService) InitSubmodules(projectPath string, paths []string) error {
+	args, err := submoduleArgs([]string{"submodule", "init"}, paths)
+	if err != nil {
+		return err
+	}
+
+	if _, err := runGit(projectPath, args...); err != nil {
+		return fmt.Errorf("failed to initialize submodules: %w", err)
+	}
+	return nil
+}
+
+// UpdateSubmodules clones missing submodules and checks out their recorded commits
+func (s *GitService) UpdateSubmodules(projectPath string, opts SubmoduleUpdateOptions) error {
+	// Submodules are cloned through the git CLI so the user's credential helpers and SSH setup apply
+	args := []string{"submodule", "update"}
+	if opts.Init {
+		args = append(args, "--init")
+	}
+	if opts.Recursive {
+		args = append(args, "--recursive")
+	}
+	if opts.Remote {
+		args = append(args, "--remote")
+	}
+
+	args, err := submoduleArgs(args, opts.Paths)
+	if err != nil {
+		return err
+	}
+
+	if _, err := runGit(projectPath, args...); err != nil {
+		return fmt.Errorf("failed to update submodules: %w", err)
+	}
+	return nil
+}
+
+// submoduleArgs appends submodule paths to git arguments
+func submoduleArgs(args []string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return args, nil
+	}
+
+	args = append(args, "--")
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("invalid submodule path: %q", path)
+		}
+		args = append(args, filepath.ToSlash(path))
+	}
+	return args, nil
+}
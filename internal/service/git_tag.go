@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// TagInfo represents a tag in the repository
+type TagInfo struct {
+	Name        string    `json:"name"`
+	Hash        string    `json:"hash"`        // Commit the tag points at
+	Annotated   bool      `json:"annotated"`   // Whether the tag has its own tag object
+	Object      string    `json:"object"`      // Hash of the tag object, only for annotated tags
+	Tagger      string    `json:"tagger"`      // Tagger name, only for annotated tags
+	TaggerEmail string    `json:"taggerEmail"` // Tagger email, only for annotated tags
+	Date        time.Time `json:"date"`        // Tagging date, or the commit date for lightweight tags
+	Message     string    `json:"message"`     // Tag message, only for annotated tags
+}
+
+// TagOptions contains options for creating a tag
+type TagOptions struct {
+	Name    string `json:"name"`
+	Target  string `json:"target"`  // Revision to tag, defaults to HEAD
+	Message string `json:"message"` // Creates an annotated tag when set, a lightweight tag otherwise
+}
+
+// ListTags returns the tags of the repository, most recent first
+func (s *GitService) ListTags(projectPath string) ([]TagInfo, error) {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := []TagInfo{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		info := TagInfo{
+			Name: ref.Name().Short(),
+			Hash: ref.Hash().String(),
+		}
+
+		if tag, err := repo.TagObject(ref.Hash()); err == nil {
+			info.Annotated = true
+			info.Object = tag.Hash.String()
+			info.Hash = tag.Target.String()
+			info.Tagger = tag.Tagger.Name
+			info.TaggerEmail = tag.Tagger.Email
+			info.Date = tag.Tagger.When
+			info.Message = strings.TrimSpace(tag.Message)
+
+			// Tags of tags are followed down to the commit
+			if commit, err := peelTag(repo, tag); err == nil {
+				info.Hash = commit.Hash.String()
+			}
+		} else if commit, err := repo.CommitObject(ref.Hash()); err == nil {
+			info.Date = commit.Committer.When
+		}
+
+		tags = append(tags, info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		if !tags[i].Date.Equal(tags[j].Date) {
+			return tags[i].Date.After(tags[j].Date)
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags, nil
+}
+
+// CreateTag creates a tag on a commit. The tag is annotated when a message is given.
+func (s *GitService) CreateTag(projectPath string, opts TagOptions) error {
+	if opts.Name == "" || strings.HasPrefix(opts.Name, "-") || plumbing.NewTagReferenceName(opts.Name).Validate() != nil {
+		return fmt.Errorf("invalid tag name: %q", opts.Name)
+	}
+
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	target := opts.Target
+	if target == "" {
+		target = "HEAD"
+	}
+	commit, err := resolveCommit(repo, target)
+	if err != nil {
+		return err
+	}
+
+	var tagOpts *git.CreateTagOptions
+	if strings.TrimSpace(opts.Message) != "" {
+		tagOpts = &git.CreateTagOptions{Message: opts.Message}
+	}
+
+	if _, err := repo.CreateTag(opts.Name, commit.Hash, tagOpts); err != nil {
+		if errors.Is(err, git.ErrTagExists) {
+			return fmt.Errorf("tag %s already exists", opts.Name)
+		}
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTag deletes a local tag
+func (s *GitService) DeleteTag(projectPath string, name string) error {
+	repo, err := s.repository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	if err := repo.DeleteTag(name); err != nil {
+		if errors.Is(err, git.ErrTagNotFound) {
+			return fmt.Errorf("tag %s not found", name)
+		}
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return nil
+}
+
+// PushTags pushes tags to a remote. With no tag names every local tag is pushed.
+func (s *GitService) PushTags(projectPath string, remote string, names []string) error {
+	if remote == "" || strings.HasPrefix(remote, "-") {
+		return fmt.Errorf("invalid remote name: %q", remote)
+	}
+
+	// Pushing goes through the git CLI so the user's credential helpers and SSH setup apply
+	args := []string{"push", remote}
+	if len(names) == 0 {
+		args = append(args, "--tags")
+	}
+	for _, name := range names {
+		if name == "" || strings.HasPrefix(name, "-") {
+			return fmt.Errorf("invalid tag name: %q", name)
+		}
+		args = append(args, plumbing.NewTagReferenceName(name).String())
+	}
+
+	if _, err := runGit(projectPath, args...); err != nil {
+		return fmt.Errorf("failed to push tags: %w", err)
+	}
+
+	return nil
+}
+
+// tagsByCommit returns the sorted tag names pointing at each commit.
+// Annotated tags are resolved to the commit they refer to. Use the cached
+// GitService.tagsByCommit where possible, this reads every tag.
+func tagsByCommit(repo *git.Repository) (map[plumbing.Hash][]string, error) {
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := make(map[plumbing.Hash][]string)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			hash = tag.Target
+			if commit, err := peelTag(repo, tag); err == nil {
+				hash = commit.Hash
+			}
+		}
+		tags[hash] = append(tags[hash], ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+
+	for _, names := range tags {
+		sort.Strings(names)
+	}
+
+	return tags, nil
+}
+
+// peelTag follows an annotated tag, and the tags it points to, down to a commit
+func peelTag(repo *git.Repository, tag *object.Tag) (*object.Commit, error) {
+	// A cycle is impossible with content-addressed objects, the depth only guards against corruption
+	for depth := 0; depth < 32; depth++ {
+		switch tag.TargetType {
+		case plumbing.CommitObject:
+			return repo.CommitObject(tag.Target)
+		case plumbing.TagObject:
+			next, err := repo.TagObject(tag.Target)
+			if err != nil {
+				return nil, err
+			}
+			tag = next
+		default:
+			return nil, fmt.Errorf("tag %s points to a %s, not a commit", tag.Name, tag.TargetType)
+		}
+	}
+	return nil, fmt.Errorf("tag %s is nested too deeply", tag.Name)
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+// EventStatusChanged is emitted when the status of an open repository changes
+const EventStatusChanged = "git:status-changed"
+
+// statusRefreshDelay batches bursts of file system events into a single status refresh
+const statusRefreshDelay = 300 * time.Millisecond
+
+// StatusChangedEvent is the payload of a status changed event
+type StatusChangedEvent struct {
+	ProjectPath string       `json:"projectPath"`
+	Files       []FileStatus `json:"files"` // New status of the repository
+}
+
+// repoWatcher watches the working tree and git directories of a repository
+type repoWatcher struct {
+	watcher     *fsnotify.Watcher
+	root        string
+	refsDir     string
+	repo        *git.Repository
+	trackedDirs map[string]bool // Ignored directories that still contain tracked files are watched
+	done        chan struct{}
+}
+
+// watch starts watching a repository so its status is refreshed, and a status
+// changed event emitted, when files change. Repositories that cannot be watched,
+// for example because the watch limit is reached, are still served without
+// reusing their status.
+func (s *GitService) watch(entry *cachedRepo) {
+	watcher, err := newRepoWatcher(entry)
+	if err != nil {
+		log.Printf("[GitService] Not watching %s: %v", entry.path, err)
+		return
+	}
+
+	entry.lock.Lock()
+	entry.watcher = watcher
+	entry.lock.Unlock()
+
+	go watcher.run(func() {
+		entry.invalidate()
+
+		entry.lock.Lock()
+		defer entry.lock.Unlock()
+		if entry.watcher == nil {
+			return
+		}
+		if entry.refreshWait != nil {
+			entry.refreshWait.Reset(statusRefreshDelay)
+			return
+		}
+		entry.refreshWait = time.AfterFunc(statusRefreshDelay, func() {
+			entry.lock.Lock()
+			closed := entry.watcher == nil
+			entry.lock.Unlock()
+			if !closed {
+				// Errors are reported to whoever asks for the status next
+				s.status(entry.path)
+			}
+		})
+	})
+}
+
+// newRepoWatcher watches the directories of the working tree that are not ignored,
+// and the git directory entries that affect the status: HEAD, the index and refs
+func newRepoWatcher(entry *cachedRepo) (*repoWatcher, error) {
+	// The watcher keeps its own repository, only used by its goroutine once started
+	repo, err := entry.repository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+	excludes, err := excludePatterns(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	w := &repoWatcher{
+		watcher:     fsWatcher,
+		root:        entry.path,
+		refsDir:     filepath.Join(entry.commonDir, "refs"),
+		repo:        repo,
+		trackedDirs: trackedDirs(idx),
+		done:        make(chan struct{}),
+	}
+
+	err = w.addWorktreeDir("", excludes)
+	if err == nil {
+		err = w.addAll(w.refsDir)
+	}
+	for _, dir := range []string{entry.gitDir, entry.commonDir} {
+		if err == nil {
+			err = fsWatcher.Add(dir)
+		}
+	}
+	if err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// run reports changes until the watcher is closed
+func (w *repoWatcher) run(changed func()) {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			// Lock files come and go while git writes the file they protect
+			if strings.HasSuffix(event.Name, ".lock") {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := w.addCreated(event.Name); err != nil {
+						log.Printf("[GitService] Failed to watch %s: %v", event.Name, err)
+					}
+				}
+			}
+			changed()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("[GitService] Watch error for %s: %v", w.root, err)
+		}
+	}
+}
+
+// addCreated starts watching a directory created after the watcher started
+func (w *repoWatcher) addCreated(dir string) error {
+	if rel, err := filepath.Rel(w.refsDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		return w.addAll(dir)
+	}
+
+	rel, err := filepath.Rel(w.root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, part := range parts {
+		if part == ".git" {
+			return nil
+		}
+	}
+
+	// Collect the ignore patterns of the parent directories
+	patterns, err := excludePatterns(w.repo)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(parts); i++ {
+		if patterns, err = appendIgnorePatterns(patterns, w.root, strings.Join(parts[:i], "/")); err != nil {
+			return err
+		}
+	}
+	if !w.trackedDirs[path.Join(parts...)] && gitignore.NewMatcher(patterns).Match(parts, true) {
+		return nil
+	}
+
+	return w.addWorktreeDir(path.Join(parts...), patterns)
+}
+
+// addWorktreeDir watches a working tree directory and its subdirectories that are not ignored
+func (w *repoWatcher) addWorktreeDir(dir string, patterns []gitignore.Pattern) error {
+	fullPath := filepath.Join(w.root, filepath.FromSlash(dir))
+	if err := w.watcher.Add(fullPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to watch %s: %w", fullPath, err)
+	}
+
+	entries, err := os.ReadDir(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	patterns, err = appendIgnorePatterns(patterns, w.root, dir)
+	if err != nil {
+		return err
+	}
+	matcher := gitignore.NewMatcher(patterns)
+
+	for _, dirEntry := range entries {
+		if !dirEntry.IsDir() || dirEntry.Name() == ".git" {
+			continue
+		}
+		rel := path.Join(dir, dirEntry.Name())
+		if !w.trackedDirs[rel] && matcher.Match(strings.Split(rel, "/"), true) {
+			continue
+		}
+		if err := w.addWorktreeDir(rel, patterns); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addAll watches a directory and all its subdirectories
+func (w *repoWatcher) addAll(dir string) error {
+	return filepath.WalkDir(dir, func(path string, dirEntry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !dirEntry.IsDir() {
+			return nil
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// close stops watching
+func (w *repoWatcher) close() {
+	close(w.done)
+	w.watcher.Close()
+}
+
+// trackedDirs returns the directories containing tracked files
+func trackedDirs(idx *index.Index) map[string]bool {
+	dirs := make(map[string]bool)
+	for _, entry := range idx.Entries {
+		for parent := path.Dir(entry.Name); parent != "." && !dirs[parent]; parent = path.Dir(parent) {
+			dirs[parent] = true
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// WorktreeInfo describes a working tree of the repository
+type WorktreeInfo struct {
+	Path       string `json:"path"`
+	Head       string `json:"head"`       // Commit checked out in the working tree
+	Branch     string `json:"branch"`     // Checked-out branch, empty when detached
+	IsMain     bool   `json:"isMain"`     // The main working tree, as opposed to a linked one
+	IsCurrent  bool   `json:"isCurrent"`  // The working tree of the given project
+	Bare       bool   `json:"bare"`       // The main repository is bare
+	Detached   bool   `json:"detached"`   // HEAD is detached
+	Locked     bool   `json:"locked"`     // Protected from being pruned or removed
+	LockReason string `json:"lockReason"` // Reason given when locking
+	Prunable   bool   `json:"prunable"`   // The working tree directory is gone
+}
+
+// WorktreeOptions contains options for creating a linked working tree
+type WorktreeOptions struct {
+	Path       string `json:"path"`       // Directory of the new working tree, relative paths are resolved against the project
+	Branch     string `json:"branch"`     // Branch to check out, empty to detach at StartPoint
+	NewBranch  bool   `json:"newBranch"`  // Create Branch at StartPoint
+	StartPoint string `json:"startPoint"` // Revision to start from, defaults to HEAD
+}
+
+// ListWorktrees returns the main and linked working trees of the repository
+func (s *GitService) ListWorktrees(projectPath string) ([]WorktreeInfo, error) {
+	// go-git does not manage linked working trees, they are listed through the git CLI
+	out, err := runGit(projectPath, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	current, err := runGit(projectPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree root: %w", err)
+	}
+	currentPath := samePathKey(strings.TrimSpace(current))
+
+	worktrees := []WorktreeInfo{}
+	for i, block := range strings.Split(strings.TrimSpace(out), "\n\n") {
+		var info WorktreeInfo
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				info.Path = filepath.FromSlash(value)
+			case "HEAD":
+				info.Head = value
+			case "branch":
+				info.Branch = plumbing.ReferenceName(value).Short()
+			case "bare":
+				info.Bare = true
+			case "detached":
+				info.Detached = true
+			case "locked":
+				info.Locked = true
+				info.LockReason = value
+			case "prunable":
+				info.Prunable = true
+			}
+		}
+		if info.Path == "" {
+			continue
+		}
+
+		info.IsMain = i == 0
+		info.IsCurrent = samePathKey(info.Path) == currentPath
+		worktrees = append(worktrees, info)
+	}
+
+	return worktrees, nil
+}
+
+// AddWorktree creates a linked working tree so another branch can be opened side by side
+func (s *GitService) AddWorktree(projectPath string, opts WorktreeOptions) (*WorktreeInfo, error) {
+	if strings.TrimSpace(opts.Path) == "" {
+		return nil, errors.New("worktree path is required")
+	}
+	for _, value := range []string{opts.Branch, opts.StartPoint} {
+		if strings.HasPrefix(value, "-") {
+			return nil, fmt.Errorf("invalid revision: %q", value)
+		}
+	}
+	if opts.NewBranch && opts.Branch == "" {
+		return nil, errors.New("a branch name is required to create a branch")
+	}
+
+	path := opts.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectPath, path)
+	}
+	path = filepath.Clean(path)
+
+	args := []string{"worktree", "add"}
+	switch {
+	case opts.NewBranch:
+		args = append(args, "-b", opts.Branch, "--", path)
+		if opts.StartPoint != "" {
+			args = append(args, opts.StartPoint)
+		}
+	case opts.Branch != "":
+		args = append(args, "--", path, opts.Branch)
+	default:
+		args = append(args, "--detach", "--", path)
+		if opts.StartPoint != "" {
+			args = append(args, opts.StartPoint)
+		}
+	}
+
+	if _, err := runGit(projectPath, args...); err != nil {
+		return nil, fmt.Errorf("failed to add worktree: %w", err)
+	}
+
+	worktrees, err := s.ListWorktrees(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	for i := range worktrees {
+		if samePathKey(worktrees[i].Path) == samePathKey(path) {
+			return &worktrees[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("worktree %s was created but is not listed", path)
+}
+
+// RemoveWorktree removes a linked working tree, relative paths are resolved against
+// the project. Without force, working trees with local changes are kept. Working
+// trees whose directory is already gone are pruned.
+func (s *GitService) RemoveWorktree(projectPath string, path string, force bool) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("worktree path is required")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectPath, path)
+	}
+	path = filepath.Clean(path)
+
+	worktrees, err := s.ListWorktrees(projectPath)
+	if err != nil {
+		return err
+	}
+	var target *WorktreeInfo
+	for i := range worktrees {
+		if samePathKey(worktrees[i].Path) == samePathKey(path) {
+			target = &worktrees[i]
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("%s is not a worktree of the repository", path)
+	}
+	if target.IsMain {
+		return errors.New("the main worktree cannot be removed")
+	}
+
+	if _, err := os.Stat(target.Path); os.IsNotExist(err) {
+		if _, err := runGit(projectPath, "worktree", "prune"); err != nil {
+			return fmt.Errorf("failed to prune worktrees: %w", err)
+		}
+	} else {
+		args := []string{"worktree", "remove"}
+		if force {
+			args = append(args, "--force")
+		}
+		args = append(args, "--", target.Path)
+
+		if _, err := runGit(projectPath, args...); err != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+	}
+
+	// Release the repository and watcher of the removed working tree, if it was opened
+	s.repos.remove(target.Path)
+	s.repos.remove(path)
+	return nil
+}
+
+// samePathKey normalizes a path so that different spellings of the same directory compare equal
+func samePathKey(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// ignoreRule is an ignore pattern with the file and line it comes from
+type ignoreRule struct {
+	gitignore.Pattern
+	text string // The pattern as written
+	file string
+	line int
+}
+
+// ignoreMatcher decides which paths of a working tree are ignored, the way git does.
+// The git status and the file explorer both use it, so they agree.
+type ignoreMatcher struct {
+	root     string
+	excludes []gitignore.Pattern
+	dirs     map[string][]gitignore.Pattern // Patterns that apply in each directory, loaded on first use
+}
+
+// newIgnoreMatcher returns the ignore matcher of the working tree that contains path.
+// Outside of a repository only the .gitignore files below path apply.
+func newIgnoreMatcher(path string) (*ignoreMatcher, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return &ignoreMatcher{root: path, dirs: make(map[string][]gitignore.Pattern)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	return repoIgnoreMatcher(repo)
+}
+
+// repoIgnoreMatcher returns the ignore matcher of the working tree of a repository
+func repoIgnoreMatcher(repo *git.Repository) (*ignoreMatcher, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+	excludes, err := excludePatterns(repo)
+	if err != nil {
+		return nil, err
+	}
+	return &ignoreMatcher{
+		root:     worktree.Filesystem.Root(),
+		excludes: excludes,
+		dirs:     make(map[string][]gitignore.Pattern),
+	}, nil
+}
+
+// match returns the rule deciding whether a slash-separated path relative to the root
+// is ignored, nil when no rule matches, and whether the rule excludes the path. Like
+// git, nothing can re-include a path once one of its parent directories is excluded.
+func (m *ignoreMatcher) match(rel string, isDir bool) (*ignoreRule, bool, error) {
+	parts := strings.Split(rel, "/")
+	for i := 1; i <= len(parts); i++ {
+		patterns, err := m.patterns(strings.Join(parts[:i-1], "/"))
+		if err != nil {
+			return nil, false, err
+		}
+		rule, excluded := matchIgnoreRule(patterns, parts[:i], isDir || i < len(parts))
+		if excluded || i == len(parts) {
+			return rule, excluded, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// patterns returns the patterns that apply to the entries of a directory
+func (m *ignoreMatcher) patterns(dir string) ([]gitignore.Pattern, error) {
+	if patterns, ok := m.dirs[dir]; ok {
+		return patterns, nil
+	}
+
+	parent := m.excludes
+	if dir != "" {
+		parentDir := ""
+		if i := strings.LastIndex(dir, "/"); i >= 0 {
+			parentDir = dir[:i]
+		}
+		var err error
+		if parent, err = m.patterns(parentDir); err != nil {
+			return nil, err
+		}
+	}
+	patterns, err := appendIgnorePatterns(parent, m.root, dir)
+	if err != nil {
+		return nil, err
+	}
+	m.dirs[dir] = patterns
+	return patterns, nil
+}
+
+// matchIgnoreRule returns the last pattern matching a path and whether it excludes it
+func matchIgnoreRule(patterns []gitignore.Pattern, path []string, isDir bool) (*ignoreRule, bool) {
+	for i := len(patterns) - 1; i >= 0; i-- {
+		if result := patterns[i].Match(path, isDir); result != gitignore.NoMatch {
+			rule, _ := patterns[i].(*ignoreRule)
+			return rule, result == gitignore.Exclude
+		}
+	}
+	return nil, false
+}
+
+// excludePatterns returns the repository-wide ignore patterns, of the global excludes
+// file and of info/exclude, which takes precedence
+func excludePatterns(repo *git.Repository) ([]gitignore.Pattern, error) {
+	globalFile, err := globalExcludesFile(repo)
+	if err != nil {
+		return nil, err
+	}
+	var patterns []gitignore.Pattern
+	if globalFile != "" {
+		if patterns, err = readIgnoreFile(globalFile, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := gitDir(repo)
+	if err != nil {
+		return nil, err
+	}
+	excludes, err := readIgnoreFile(filepath.Join(commonGitDir(dir), "info", "exclude"), nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(patterns, excludes...), nil
+}
+
+// globalExcludesFile returns the path of core.excludesFile, or of the default global
+// excludes file when it is not set
+func globalExcludesFile(repo *git.Repository) (string, error) {
+	cfg, err := repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	file := cfg.Raw.Section("core").Option("excludesFile")
+	switch {
+	case strings.HasPrefix(file, "~/"):
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			file = filepath.Join(homeDir, file[2:])
+		}
+	case file == "":
+		if configDir, err := os.UserConfigDir(); err == nil {
+			file = filepath.Join(configDir, "git", "ignore")
+		}
+	}
+	return file, nil
+}
+
+// appendIgnorePatterns adds the patterns of the .gitignore file of a working tree directory
+func appendIgnorePatterns(patterns []gitignore.Pattern, root string, dir string) ([]gitignore.Pattern, error) {
+	var domain []string
+	if dir != "" {
+		domain = strings.Split(dir, "/")
+	}
+
+	dirPatterns, err := readIgnoreFile(filepath.Join(root, filepath.FromSlash(dir), ".gitignore"), domain)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirPatterns) == 0 {
+		return patterns, nil
+	}
+
+	// Copy so sibling directories do not see each other's patterns
+	combined := make([]gitignore.Pattern, 0, len(patterns)+len(dirPatterns))
+	return append(append(combined, patterns...), dirPatterns...), nil
+}
+
+// readIgnoreFile parses an ignore file whose patterns apply below domain
+func readIgnoreFile(file string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, &ignoreRule{
+			Pattern: gitignore.ParsePattern(line, domain),
+			text:    line,
+			file:    file,
+			line:    lineNumber,
+		})
+	}
+	return patterns, scanner.Err()
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package service
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup does nothing on Windows, where killProcessGroup kills the process tree
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills a command and its children
+func killProcessGroup(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}